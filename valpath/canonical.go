@@ -0,0 +1,77 @@
+package valpath
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+)
+
+// Canonicalize rewrites p, which is meant to be applied to values of type t, so that every
+// field is reached by an explicit step from the struct that declares it.  Promoted fields are
+// replaced by the chain of embedded fields leading to them, with Deref steps for embedded
+// pointers, and FieldIndex and TaggedField steps are replaced by named fields.  Paths that
+// address the same location are identical after canonicalization.
+//
+// Steps after an interface has been unwrapped are left unchanged, since the types they apply
+// to aren't known until a value is available.
+func Canonicalize(t reflect.Type, p Path) (Path, error) {
+	steps := slices.Collect(p.elems())
+	var out []Path
+	for i, step := range steps {
+		next, err := step.resolveType(t)
+		if errors.Is(err, errDynamic) {
+			out = append(out, steps[i:]...)
+			break
+		}
+		if err != nil {
+			return nil, atPos(err, step, t, steps[:i], i)
+		}
+		switch step := step.(type) {
+		case ExportedFieldPart:
+			fieldDesc, _ := t.FieldByName(string(step))
+			out = append(out, explicitField(t, fieldDesc.Index, false)...)
+		case UnexportedFieldPart:
+			fieldDesc, _ := t.FieldByName(string(step))
+			out = append(out, explicitField(t, fieldDesc.Index, true)...)
+		case FieldIndexPart:
+			out = append(out, explicitField(t, step, false)...)
+		case TaggedFieldPart:
+			out = append(out, explicitField(t, step.index(t), false)...)
+		default:
+			out = append(out, step)
+		}
+		t = next
+	}
+	return Join(out...), nil
+}
+
+// explicitField returns the steps to reach the field of struct type t at index one level at
+// a time.  Unexported embedded structs along the way can only be stepped through with an
+// ExportedField or FieldIndex step, so unless unexported is set, the remaining levels are
+// kept together in a single FieldIndex.
+func explicitField(t reflect.Type, index []int, unexported bool) []Path {
+	var out []Path
+	var run []int
+	for n, i := range index {
+		fieldDesc := t.Field(i)
+		switch {
+		case len(run) > 0 || (!fieldDesc.IsExported() && !unexported):
+			run = append(run, i)
+		case fieldDesc.IsExported():
+			out = append(out, ExportedField(fieldDesc.Name))
+		default:
+			out = append(out, UnexportedField(fieldDesc.Name))
+		}
+		t = fieldDesc.Type
+		if n < len(index)-1 && t.Kind() == reflect.Pointer {
+			if len(run) == 0 {
+				out = append(out, Deref())
+			}
+			t = t.Elem()
+		}
+	}
+	if len(run) > 0 {
+		out = append(out, FieldIndex(run))
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+type lowerEmbed struct {
+	Exported int
+	hidden   int
+}
+
+type viaLower struct {
+	lowerEmbed
+}
+
+func TestCanonicalize(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   any
+		path valpath.Path
+		want valpath.Path
+	}{
+		{
+			name: "promoted field by value",
+			in:   testtypes.Outer{Inner: testtypes.Inner{Int: 42}},
+			path: valpath.ExportedField("Int"),
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.ExportedField("Int")),
+		},
+		{
+			name: "promoted field by pointer",
+			in:   testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 42}},
+			path: valpath.ExportedField("Int"),
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name: "explicit path is unchanged",
+			in:   testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 42}},
+			path: valpath.Join(valpath.ExportedField("Inner"), valpath.Deref(), valpath.ExportedField("Int")),
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name: "field index",
+			in:   testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 42}},
+			path: valpath.FieldIndex([]int{0, 0}),
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name: "promoted through unexported embedded struct",
+			in:   viaLower{lowerEmbed: lowerEmbed{Exported: 42}},
+			path: valpath.ExportedField("Exported"),
+			want: valpath.FieldIndex([]int{0, 0}),
+		},
+		{
+			name: "promoted unexported field",
+			in:   viaLower{lowerEmbed: lowerEmbed{hidden: 42}},
+			path: valpath.UnexportedField("hidden"),
+			want: valpath.Join(valpath.UnexportedField("lowerEmbed"), valpath.UnexportedField("hidden")),
+		},
+		{
+			name: "steps after an interface are unchanged",
+			in:   struct{ Any any }{Any: testtypes.Outer{Inner: testtypes.Inner{Int: 42}}},
+			path: valpath.Join(valpath.ExportedField("Any"), valpath.Inter(), valpath.ExportedField("Int")),
+			want: valpath.Join(valpath.ExportedField("Any"), valpath.Inter(), valpath.ExportedField("Int")),
+		},
+		{
+			name: "nested in containers",
+			in:   map[string][]testtypes.Outer{"a": {{Inner: testtypes.Inner{Int: 42}}}},
+			path: valpath.Join(valpath.MapValueOfKey("a"), valpath.Index(0), valpath.ExportedField("Int")),
+			want: valpath.Join(valpath.MapValueOfKey("a"), valpath.Index(0), valpath.ExportedField("Inner"), valpath.ExportedField("Int")),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			in := reflect.ValueOf(tt.in)
+			got, err := valpath.Canonicalize(in.Type(), tt.path)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !valpath.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+
+			// The canonical path must address the same value as the original.
+			want, err := tt.path.Traverse(in)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			found, err := got.Traverse(in)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !reflect.DeepEqual(found.Interface(), want.Interface()) {
+				t.Errorf("got value %v, want %v", found.Interface(), want.Interface())
+			}
+		})
+	}
+
+	t.Run("invalid path", func(t *testing.T) {
+		_, err := valpath.Canonicalize(
+			reflect.TypeFor[testtypes.OuterPtr](),
+			valpath.Join(valpath.ExportedField("Inner"), valpath.Deref(), valpath.ExportedField("Missing")))
+		if !errors.Is(err, valpath.ErrNoSuchField) {
+			t.Fatalf("got error %v, want %v", err, valpath.ErrNoSuchField)
+		}
+		var pathErr *valpath.PathError
+		if !errors.As(err, &pathErr) || pathErr.Pos != 2 {
+			t.Errorf("got error %v, want error at step 2", err)
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package valpath
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// stepKey describes a single step in a form that can be compared.
+type stepKey struct {
+	// rank orders different kinds of steps relative to each other.
+	rank int
+	// tag identifies the kind of step in the output of Key.
+	tag   string
+	name  string
+	index []int
+	key   reflect.Value
+	// typ is the type of an AsType step, which name only identifies up to types with the
+	// same name.
+	typ reflect.Type
+}
+
+func keyOf(step Path) stepKey {
+	switch s := step.(type) {
+	case FieldIndexPart:
+		return stepKey{rank: 0, tag: "#", index: s}
+	case ExportedFieldPart:
+		return stepKey{rank: 1, tag: "F", name: string(s)}
+	case TaggedFieldPart:
+		// Tag keys can't contain colons, so this is unambiguous.
+		return stepKey{rank: 2, tag: "T", name: s.Key + ":" + s.Name}
+	case UnexportedFieldPart:
+		return stepKey{rank: 3, tag: "U", name: string(s)}
+	case MethodPart:
+		return stepKey{rank: 4, tag: "M", name: string(s)}
+	case IndexPart:
+		return stepKey{rank: 5, tag: "I", index: []int{int(s)}}
+	case FromEndPart:
+		return stepKey{rank: 6, tag: "E", index: []int{int(s)}}
+	case SliceRangePart:
+		return stepKey{rank: 7, tag: "S", index: []int{s.Lo, s.Hi}}
+	case ByteAtPart:
+		return stepKey{rank: 8, tag: "B", index: []int{int(s)}}
+	case RuneAtPart:
+		return stepKey{rank: 9, tag: "R", index: []int{int(s)}}
+	case MapKeyPart:
+		return stepKey{rank: 10, tag: "K", key: reflect.Value(s)}
+	case MapValueOfKeyPart:
+		return stepKey{rank: 11, tag: "V", key: reflect.Value(s)}
+	case DerefPart:
+		return stepKey{rank: 12, tag: "D"}
+	case InterPart:
+		return stepKey{rank: 13, tag: "?"}
+	case AsTypePart:
+		return stepKey{rank: 14, tag: "A", name: typeName(s.Type), typ: s.Type}
+	case AddrPart:
+		return stepKey{rank: 15, tag: "&"}
+	default:
+		panic(fmt.Sprintf("valpath: unknown step type %T", step))
+	}
+}
+
+// Equal reports whether a and b consist of the same steps.  Map keys are equal if they have
+// the same type and compare equal with ==.
+func Equal(a, b Path) bool {
+	aSteps := slices.Collect(a.elems())
+	bSteps := slices.Collect(b.elems())
+	return slices.EqualFunc(aSteps, bSteps, stepEqual)
+}
+
+func stepEqual(a, b Path) bool {
+	aKey := keyOf(a)
+	bKey := keyOf(b)
+	return aKey.rank == bKey.rank &&
+		aKey.name == bKey.name &&
+		aKey.typ == bKey.typ &&
+		slices.Equal(aKey.index, bKey.index) &&
+		keysEqual(aKey.key, bKey.key)
+}
+
+// Compare returns a stable total order over paths, suitable for sorting.  Paths are compared
+// step by step, and a path sorts before any longer path that it is a prefix of.  Field steps
+// sort before indexes, which sort before map keys; fields of the same kind are ordered by
+// index or name, indexes numerically, and map keys by type and then by value.  Distinct types
+// with the same name are not ordered.  CompareIn orders fields as they are declared instead.
+func Compare(a, b Path) int {
+	aSteps := slices.Collect(a.elems())
+	bSteps := slices.Collect(b.elems())
+	return slices.CompareFunc(aSteps, bSteps, compareSteps)
+}
+
+// CompareIn is like Compare, but field steps that apply to structs within values of type t
+// are ordered by the order in which the fields are declared.  Steps whose types can't be
+// resolved are ordered as by Compare.
+func CompareIn(t reflect.Type, a, b Path) int {
+	aSteps := slices.Collect(a.elems())
+	bSteps := slices.Collect(b.elems())
+	for i := range min(len(aSteps), len(bSteps)) {
+		aStep, bStep := aSteps[i], bSteps[i]
+		aIndex, aOK := declaredIndex(t, aStep)
+		bIndex, bOK := declaredIndex(t, bStep)
+		if aOK && bOK {
+			if c := slices.Compare(aIndex, bIndex); c != 0 {
+				return c
+			}
+		}
+		if c := compareSteps(aStep, bStep); c != 0 {
+			return c
+		}
+		next, err := aStep.resolveType(t)
+		if err != nil {
+			next = nil
+		}
+		t = next
+	}
+	return cmp.Compare(len(aSteps), len(bSteps))
+}
+
+// declaredIndex returns the index sequence of the field that step addresses within the struct
+// type t, if step is a field step that applies to t.
+func declaredIndex(t reflect.Type, step Path) ([]int, bool) {
+	if t == nil {
+		return nil, false
+	}
+	if _, err := step.resolveType(t); err != nil {
+		return nil, false
+	}
+	switch step := step.(type) {
+	case ExportedFieldPart:
+		fieldDesc, _ := t.FieldByName(string(step))
+		return fieldDesc.Index, true
+	case UnexportedFieldPart:
+		fieldDesc, _ := t.FieldByName(string(step))
+		return fieldDesc.Index, true
+	case FieldIndexPart:
+		return step, true
+	case TaggedFieldPart:
+		return step.index(t), true
+	default:
+		return nil, false
+	}
+}
+
+func compareSteps(a, b Path) int {
+	aKey := keyOf(a)
+	bKey := keyOf(b)
+	return cmp.Or(
+		cmp.Compare(aKey.rank, bKey.rank),
+		strings.Compare(aKey.name, bKey.name),
+		slices.Compare(aKey.index, bKey.index),
+		compareKeys(aKey.key, bKey.key),
+	)
+}
+
+// Key returns a string that uniquely identifies p, so that paths can be used as map keys.
+// Key(a) == Key(b) if and only if Equal(a, b), except for map keys like NaN that aren't
+// equal to themselves, struct or array keys that contain negative zeros, and As steps or map
+// keys whose types are distinct but have the same name, such as types declared in different
+// functions of the same package.
+func Key(p Path) string {
+	b := &strings.Builder{}
+	for step := range p.elems() {
+		k := keyOf(step)
+		b.WriteString(k.tag)
+		switch {
+		case k.name != "":
+			b.WriteString(strconv.Quote(k.name))
+		case k.index != nil:
+			b.WriteString(fmt.Sprint(k.index))
+		case k.key.IsValid():
+			b.WriteString(strconv.Quote(typeName(k.key.Type())))
+			b.WriteString(strconv.Quote(fmt.Sprintf("%#v", positiveZero(k.key).Interface())))
+		}
+		b.WriteString("/")
+	}
+	return b.String()
+}
+
+// typeName names t, qualifying named types by their full package path so that types from
+// different packages with the same name are told apart.
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
+
+// positiveZero returns k with negative zeros in a float or complex key replaced by positive
+// ones, which compare equal to them but are formatted differently.
+func positiveZero(k reflect.Value) reflect.Value {
+	switch k.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if k.Float() == 0 {
+			return reflect.Zero(k.Type())
+		}
+	case reflect.Complex64, reflect.Complex128:
+		c := k.Complex()
+		// Adding positive zero turns a negative zero into a positive one, and leaves other
+		// values unchanged.
+		return reflect.ValueOf(c + 0).Convert(k.Type())
+	}
+	return k
+}
+
+func keysEqual(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() || !a.Comparable() || !b.Comparable() {
+		return false
+	}
+	return a.Equal(b)
+}
+
+func compareKeys(a, b reflect.Value) int {
+	if keysEqual(a, b) {
+		return 0
+	}
+	if !a.IsValid() || !b.IsValid() {
+		return cmp.Compare(boolInt(a.IsValid()), boolInt(b.IsValid()))
+	}
+	if a.Type() != b.Type() {
+		return strings.Compare(typeName(a.Type()), typeName(b.Type()))
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Bool:
+		return cmp.Compare(boolInt(a.Bool()), boolInt(b.Bool()))
+	default:
+		return strings.Compare(fmt.Sprintf("%#v", a.Interface()), fmt.Sprintf("%#v", b.Interface()))
+	}
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
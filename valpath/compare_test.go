@@ -0,0 +1,235 @@
+package valpath_test
+
+import (
+	"math"
+	randv1 "math/rand"
+	randv2 "math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+// otherPoint returns a type with the same name as the point type declared in TestEqual.
+func otherPoint() reflect.Type {
+	type point struct{ X, Y int }
+	return reflect.TypeFor[point]()
+}
+
+func TestEqual(t *testing.T) {
+	type point struct{ X, Y int }
+	testCases := []struct {
+		name string
+		a, b valpath.Path
+		want bool
+	}{
+		{
+			name: "empty paths",
+			a:    valpath.Empty(),
+			b:    valpath.Join(),
+			want: true,
+		},
+		{
+			name: "separately constructed map keys",
+			a:    valpath.Join(valpath.ExportedField("M"), valpath.MapValueOfKey("k")),
+			b:    valpath.Join(valpath.ExportedField("M"), valpath.MapValueOfKey("k")),
+			want: true,
+		},
+		{
+			name: "nested joins",
+			a:    valpath.Join(valpath.Join(valpath.ExportedField("A"), valpath.Index(1)), valpath.Deref()),
+			b:    valpath.Join(valpath.ExportedField("A"), valpath.Join(valpath.Index(1), valpath.Deref())),
+			want: true,
+		},
+		{
+			name: "struct map keys",
+			a:    valpath.MapKey(point{X: 1, Y: 2}),
+			b:    valpath.MapKey(point{X: 1, Y: 2}),
+			want: true,
+		},
+		{
+			name: "different key types",
+			a:    valpath.MapValueOfKey(1),
+			b:    valpath.MapValueOfKey(int64(1)),
+			want: false,
+		},
+		{
+			name: "same type assertions",
+			a:    valpath.As[point](),
+			b:    valpath.AsType(reflect.TypeFor[point]()),
+			want: true,
+		},
+		{
+			name: "map key vs map value",
+			a:    valpath.MapKey("k"),
+			b:    valpath.MapValueOfKey("k"),
+			want: false,
+		},
+		{
+			name: "different field names",
+			a:    valpath.ExportedField("A"),
+			b:    valpath.ExportedField("B"),
+			want: false,
+		},
+		{
+			name: "exported vs unexported",
+			a:    valpath.ExportedField("a"),
+			b:    valpath.UnexportedField("a"),
+			want: false,
+		},
+		{
+			name: "field indexes",
+			a:    valpath.FieldIndex([]int{0, 1}),
+			b:    valpath.FieldIndex([]int{0, 1}),
+			want: true,
+		},
+		{
+			name: "prefix",
+			a:    valpath.ExportedField("A"),
+			b:    valpath.Join(valpath.ExportedField("A"), valpath.Deref()),
+			want: false,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := valpath.Equal(tt.a, tt.b); got != tt.want {
+				t.Errorf("got Equal() = %v, want %v", got, tt.want)
+			}
+			if got := valpath.Compare(tt.a, tt.b) == 0; got != tt.want {
+				t.Errorf("got Compare() == 0 is %v, want %v", got, tt.want)
+			}
+			if got := valpath.Key(tt.a) == valpath.Key(tt.b); got != tt.want {
+				t.Errorf("got Key() equal is %v, want %v (%q vs %q)", got, tt.want, valpath.Key(tt.a), valpath.Key(tt.b))
+			}
+		})
+	}
+
+	// Types with the same name can't be told apart by Compare or Key, as documented.
+	t.Run("type assertions to distinct types with the same name", func(t *testing.T) {
+		if valpath.Equal(valpath.As[point](), valpath.AsType(otherPoint())) {
+			t.Error("got Equal() = true, want false")
+		}
+	})
+}
+
+func TestCompare(t *testing.T) {
+	want := []valpath.Path{
+		valpath.Empty(),
+		valpath.FieldIndex([]int{0}),
+		valpath.FieldIndex([]int{0, 1}),
+		valpath.FieldIndex([]int{1}),
+		valpath.ExportedField("A"),
+		valpath.Join(valpath.ExportedField("A"), valpath.Index(2)),
+		valpath.Join(valpath.ExportedField("A"), valpath.Index(10)),
+		valpath.ExportedField("B"),
+		valpath.UnexportedField("a"),
+		valpath.Method("Get"),
+		valpath.Index(0),
+		valpath.MapKey("a"),
+		valpath.MapValueOfKey(2),
+		valpath.MapValueOfKey(10),
+		valpath.MapValueOfKey(int64(1)),
+		valpath.MapValueOfKey("a"),
+		valpath.MapValueOfKey("b"),
+		valpath.Deref(),
+		valpath.Inter(),
+	}
+	got := slices.Clone(want)
+	slices.Reverse(got)
+	slices.SortStableFunc(got, valpath.Compare)
+	if !slices.EqualFunc(got, want, valpath.Equal) {
+		t.Errorf("got order %v, want %v", got, want)
+	}
+}
+
+func TestKey(t *testing.T) {
+	seen := map[string]valpath.Path{}
+	for _, p := range []valpath.Path{
+		valpath.Empty(),
+		valpath.ExportedField("A"),
+		valpath.ExportedField("A/B"),
+		valpath.Join(valpath.ExportedField("A"), valpath.ExportedField("B")),
+		valpath.MapValueOfKey("A"),
+		valpath.MapValueOfKey(`"A"`),
+		valpath.MapValueOfKey(1),
+		valpath.MapValueOfKey(uint(1)),
+		valpath.Index(1),
+		valpath.FieldIndex([]int{1}),
+		// These types are both named rand.Rand.
+		valpath.MapValueOfKey(randv1.Rand{}),
+		valpath.MapValueOfKey(randv2.Rand{}),
+	} {
+		key := valpath.Key(p)
+		if other, ok := seen[key]; ok {
+			t.Errorf("paths %v and %v have the same key %q", p, other, key)
+		}
+		seen[key] = p
+	}
+
+	for _, pair := range [][2]valpath.Path{
+		{valpath.MapValueOfKey(0.0), valpath.MapValueOfKey(math.Copysign(0, -1))},
+		{valpath.MapValueOfKey(complex(0, 0)), valpath.MapValueOfKey(complex(math.Copysign(0, -1), math.Copysign(0, -1)))},
+	} {
+		if !valpath.Equal(pair[0], pair[1]) {
+			t.Fatalf("paths %v and %v are not equal", pair[0], pair[1])
+		}
+		if valpath.Key(pair[0]) != valpath.Key(pair[1]) {
+			t.Errorf("equal paths %v and %v have different keys", pair[0], pair[1])
+		}
+	}
+
+	p := valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey("env"), valpath.Deref())
+	text, err := valpath.Format(p)
+	if err != nil {
+		t.Fatalf("got error %v, want no error", err)
+	}
+	parsed, err := valpath.Parse(text)
+	if err != nil {
+		t.Fatalf("got error %v, want no error", err)
+	}
+	if _, ok := seen[valpath.Key(parsed)]; ok {
+		t.Errorf("parsed path %v collides with an unrelated path", parsed)
+	}
+	seen[valpath.Key(p)] = p
+	if _, ok := seen[valpath.Key(parsed)]; !ok {
+		t.Errorf("parsed path %v has a different key than %v", parsed, p)
+	}
+}
+
+func TestCompareIn(t *testing.T) {
+	type inner struct {
+		Z int
+		A int
+	}
+	type root struct {
+		B     int
+		Inner inner
+		A     int
+		Items []inner
+		Any   any
+	}
+	want := []valpath.Path{
+		valpath.Empty(),
+		valpath.ExportedField("B"),
+		valpath.ExportedField("Inner"),
+		valpath.Join(valpath.ExportedField("Inner"), valpath.ExportedField("Z")),
+		valpath.Join(valpath.ExportedField("Inner"), valpath.FieldIndex([]int{1})),
+		valpath.Join(valpath.ExportedField("Inner"), valpath.ExportedField("A")),
+		valpath.ExportedField("A"),
+		valpath.Join(valpath.ExportedField("Items"), valpath.Index(0), valpath.ExportedField("Z")),
+		valpath.Join(valpath.ExportedField("Items"), valpath.Index(0), valpath.ExportedField("A")),
+		valpath.Join(valpath.ExportedField("Items"), valpath.Index(1)),
+		// The fields of interface contents aren't known, so they're ordered by name.
+		valpath.Join(valpath.ExportedField("Any"), valpath.Inter(), valpath.ExportedField("A")),
+		valpath.Join(valpath.ExportedField("Any"), valpath.Inter(), valpath.ExportedField("Z")),
+	}
+	got := slices.Clone(want)
+	slices.Reverse(got)
+	slices.SortStableFunc(got, func(a, b valpath.Path) int {
+		return valpath.CompareIn(reflect.TypeFor[root](), a, b)
+	})
+	if !slices.EqualFunc(got, want, valpath.Equal) {
+		t.Errorf("got order %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,193 @@
+package valpath
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+)
+
+// Accessor is a Path that has been compiled against a root type, so that field lookups and
+// kind checks are done once up front.  Get, and Set for paths that only go through struct
+// fields, pointers, and slice or array elements, don't allocate.
+type Accessor struct {
+	root  reflect.Type
+	leaf  reflect.Type
+	steps []Path
+	ops   []op
+	// inPlace is true if every op yields a view into the root value, so that Set can assign to
+	// the location directly instead of copying and storing back intermediate values.
+	inPlace bool
+}
+
+type opKind int
+
+const (
+	opField opKind = iota
+	opDeref
+	opIndex
+	opMapValue
+	// opStep falls back to the step's own Traverse method.
+	opStep
+)
+
+type op struct {
+	kind opKind
+	i    int
+	key  reflect.Value
+	// pos is the position of the step that produced this op.
+	pos int
+	// readOnly is true if the op follows an UnexportedField step, so that its result is copied
+	// as described by ReadOnly.
+	readOnly bool
+}
+
+// Compile checks p against values of type t, as ResolveType does, and prepares an Accessor
+// for it.  Steps after an interface has been unwrapped can't be prepared, and are traversed
+// as usual each time.
+func Compile(t reflect.Type, p Path) (*Accessor, error) {
+	a := &Accessor{
+		root:    t,
+		steps:   slices.Collect(p.elems()),
+		inPlace: true,
+	}
+	var hidden readOnlyTracker
+	for pos, step := range a.steps {
+		next, err := step.resolveType(t)
+		if errors.Is(err, errDynamic) {
+			for ; pos < len(a.steps); pos++ {
+				a.ops = append(a.ops, op{kind: opStep, pos: pos, readOnly: hidden.step(a.steps[pos])})
+			}
+			a.leaf = Dynamic
+			a.inPlace = false
+			return a, nil
+		}
+		if err != nil {
+			return nil, atPos(err, step, t, a.steps[:pos], pos)
+		}
+
+		start := len(a.ops)
+		switch step := step.(type) {
+		case ExportedFieldPart:
+			fieldDesc, _ := t.FieldByName(string(step))
+			a.fieldOps(t, fieldDesc.Index, pos)
+		case FieldIndexPart:
+			a.fieldOps(t, step, pos)
+		case TaggedFieldPart:
+			a.fieldOps(t, step.index(t), pos)
+		case DerefPart:
+			a.ops = append(a.ops, op{kind: opDeref, pos: pos})
+		case IndexPart:
+			a.ops = append(a.ops, op{kind: opIndex, i: int(step), pos: pos})
+		case MapValueOfKeyPart:
+			key, _ := mapKey(reflect.Value(step), t.Key())
+			a.ops = append(a.ops, op{kind: opMapValue, key: key, pos: pos})
+			a.inPlace = false
+		default:
+			a.ops = append(a.ops, op{kind: opStep, pos: pos})
+			a.inPlace = false
+		}
+		ro := hidden.step(step)
+		for i := start; i < len(a.ops); i++ {
+			a.ops[i].readOnly = ro
+		}
+		t = next
+	}
+	a.leaf = t
+	return a, nil
+}
+
+func (a *Accessor) fieldOps(t reflect.Type, index []int, pos int) {
+	for n, i := range index {
+		fieldDesc := t.Field(i)
+		a.ops = append(a.ops, op{kind: opField, i: i, pos: pos})
+		t = fieldDesc.Type
+		if n < len(index)-1 && t.Kind() == reflect.Pointer {
+			a.ops = append(a.ops, op{kind: opDeref, pos: pos})
+			t = t.Elem()
+		}
+	}
+}
+
+// Type returns the type of the values that a addresses, or Dynamic if that depends on the
+// dynamic type of an interface value.
+func (a *Accessor) Type() reflect.Type {
+	return a.leaf
+}
+
+// Path returns the path that a was compiled from.
+func (a *Accessor) Path() Path {
+	return Join(a.steps...)
+}
+
+func (a *Accessor) fail(pos int, v reflect.Value, err error) error {
+	var step Path = Empty()
+	if pos < len(a.steps) {
+		step = a.steps[pos]
+	}
+	return atPos(err, step, typeOf(v), a.steps[:pos], pos)
+}
+
+// Get returns the value that a addresses within root, which must have the type that a was
+// compiled for.
+func (a *Accessor) Get(root reflect.Value) (reflect.Value, error) {
+	if !root.IsValid() {
+		return zeroValue, a.fail(0, root, ErrInvalidValue)
+	}
+	if root.Type() != a.root {
+		return zeroValue, a.fail(0, root, ErrKindMismatch)
+	}
+	v := root
+	for _, o := range a.ops {
+		switch o.kind {
+		case opField:
+			v = v.Field(o.i)
+		case opDeref:
+			if v.IsNil() {
+				return zeroValue, a.fail(o.pos, v, ErrNilPointer)
+			}
+			v = v.Elem()
+		case opIndex:
+			if o.i >= v.Len() {
+				return zeroValue, a.fail(o.pos, v, ErrIndexOutOfRange)
+			}
+			v = v.Index(o.i)
+		case opMapValue:
+			found := zeroValue
+			if !v.IsNil() {
+				found = v.MapIndex(o.key)
+			}
+			if !found.IsValid() {
+				return zeroValue, a.fail(o.pos, v, ErrKeyNotFound)
+			}
+			v = found
+		case opStep:
+			next, err := a.steps[o.pos].Traverse(v)
+			if err != nil {
+				return zeroValue, a.fail(o.pos, v, err)
+			}
+			v = next
+		}
+		if o.readOnly {
+			v = readOnly(v)
+		}
+	}
+	return v, nil
+}
+
+// Set stores newVal at the location that a addresses within root, in the same way as Set.
+func (a *Accessor) Set(root reflect.Value, newVal reflect.Value) error {
+	if !a.inPlace {
+		if root.IsValid() && root.Type() != a.root {
+			return a.fail(0, root, ErrKindMismatch)
+		}
+		return Set(root, a.Path(), newVal)
+	}
+	v, err := a.Get(root)
+	if err != nil {
+		return err
+	}
+	if err := assign(v, newVal); err != nil {
+		return leafError(a.Path(), typeOf(v), err)
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+type compileRoot struct {
+	Outer testtypes.OuterPtr
+	Items []testtypes.Inner
+	Fixed [2]testtypes.Inner
+	Tags  map[string]*testtypes.Inner
+	IFace testtypes.IFace
+	Any   any
+}
+
+func newCompileRoot() *compileRoot {
+	return &compileRoot{
+		Outer: testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 1}},
+		Items: []testtypes.Inner{{Int: 2}, {Int: 3}},
+		Fixed: [2]testtypes.Inner{{Int: 4}, {Int: 5}},
+		Tags:  map[string]*testtypes.Inner{"a": {Int: 6}},
+		IFace: testtypes.IFaceImpl(7),
+		Any:   testtypes.Outer{Inner: testtypes.Inner{Int: 8}},
+	}
+}
+
+func TestCompile(t *testing.T) {
+	root := reflect.ValueOf(newCompileRoot()).Elem()
+	nilOuter := reflect.ValueOf(compileRoot{})
+	testCases := []struct {
+		name     string
+		in       reflect.Value
+		path     valpath.Path
+		wantType reflect.Type
+		wantErr  error
+		wantPos  int
+	}{
+		{
+			name:     "empty",
+			in:       root,
+			path:     valpath.Empty(),
+			wantType: reflect.TypeFor[compileRoot](),
+		},
+		{
+			name:     "promoted field through pointer",
+			in:       root,
+			path:     valpath.Join(valpath.ExportedField("Outer"), valpath.ExportedField("Int")),
+			wantType: reflect.TypeFor[int](),
+		},
+		{
+			name:     "field index",
+			in:       root,
+			path:     valpath.FieldIndex([]int{0, 0, 0}),
+			wantType: reflect.TypeFor[int](),
+		},
+		{
+			name:     "slice element",
+			in:       root,
+			path:     valpath.Join(valpath.ExportedField("Items"), valpath.Index(1), valpath.ExportedField("Int")),
+			wantType: reflect.TypeFor[int](),
+		},
+		{
+			name:     "array element",
+			in:       root,
+			path:     valpath.Join(valpath.ExportedField("Fixed"), valpath.Index(1)),
+			wantType: reflect.TypeFor[testtypes.Inner](),
+		},
+		{
+			name:     "map value",
+			in:       root,
+			path:     valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey("a"), valpath.Deref(), valpath.ExportedField("Int")),
+			wantType: reflect.TypeFor[int](),
+		},
+		{
+			name:     "method",
+			in:       root,
+			path:     valpath.Join(valpath.ExportedField("IFace"), valpath.Method("String")),
+			wantType: reflect.TypeFor[string](),
+		},
+		{
+			name:     "dynamic",
+			in:       root,
+			path:     valpath.Join(valpath.ExportedField("Any"), valpath.Inter(), valpath.ExportedField("Int")),
+			wantType: valpath.Dynamic,
+		},
+		{
+			name:     "nil embedded pointer",
+			in:       nilOuter,
+			path:     valpath.Join(valpath.ExportedField("Outer"), valpath.ExportedField("Int")),
+			wantType: reflect.TypeFor[int](),
+			wantErr:  valpath.ErrNilPointer,
+			wantPos:  1,
+		},
+		{
+			name:     "index out of range",
+			in:       root,
+			path:     valpath.Join(valpath.ExportedField("Items"), valpath.Index(2)),
+			wantType: reflect.TypeFor[testtypes.Inner](),
+			wantErr:  valpath.ErrIndexOutOfRange,
+			wantPos:  1,
+		},
+		{
+			name:     "missing key",
+			in:       root,
+			path:     valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey("b")),
+			wantType: reflect.TypeFor[*testtypes.Inner](),
+			wantErr:  valpath.ErrKeyNotFound,
+			wantPos:  1,
+		},
+		{
+			name:     "nil map",
+			in:       nilOuter,
+			path:     valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey("a")),
+			wantType: reflect.TypeFor[*testtypes.Inner](),
+			wantErr:  valpath.ErrKeyNotFound,
+			wantPos:  1,
+		},
+		{
+			name:     "dynamic step fails",
+			in:       root,
+			path:     valpath.Join(valpath.ExportedField("Any"), valpath.Inter(), valpath.ExportedField("Missing")),
+			wantType: valpath.Dynamic,
+			wantErr:  valpath.ErrNoSuchField,
+			wantPos:  2,
+		},
+		{
+			name:     "wrong root type",
+			in:       reflect.ValueOf(42),
+			path:     valpath.ExportedField("Items"),
+			wantType: reflect.TypeFor[[]testtypes.Inner](),
+			wantErr:  valpath.ErrKindMismatch,
+			wantPos:  0,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := valpath.Compile(reflect.TypeFor[compileRoot](), tt.path)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if a.Type() != tt.wantType {
+				t.Errorf("got type %v, want %v", a.Type(), tt.wantType)
+			}
+			got, err := a.Get(tt.in)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				var pathErr *valpath.PathError
+				if !errors.As(err, &pathErr) || pathErr.Pos != tt.wantPos {
+					t.Errorf("got error %v, want error at step %d", err, tt.wantPos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			want, err := tt.path.Traverse(tt.in)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !reflect.DeepEqual(got.Interface(), want.Interface()) {
+				t.Errorf("got value %v, want %v", got.Interface(), want.Interface())
+			}
+		})
+	}
+
+	t.Run("invalid path", func(t *testing.T) {
+		_, err := valpath.Compile(reflect.TypeFor[compileRoot](), valpath.Join(valpath.ExportedField("Items"), valpath.Deref()))
+		if !errors.Is(err, valpath.ErrKindMismatch) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrKindMismatch)
+		}
+	})
+}
+
+func TestAccessorSet(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		path valpath.Path
+		want func(*compileRoot)
+	}{
+		{
+			name: "in place",
+			path: valpath.Join(valpath.ExportedField("Fixed"), valpath.Index(1), valpath.ExportedField("Int")),
+			want: func(r *compileRoot) { r.Fixed[1].Int = 42 },
+		},
+		{
+			name: "through map",
+			path: valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey("a"), valpath.Deref(), valpath.ExportedField("Int")),
+			want: func(r *compileRoot) { r.Tags["a"].Int = 42 },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := valpath.Compile(reflect.TypeFor[compileRoot](), tt.path)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			got := newCompileRoot()
+			if err := a.Set(reflect.ValueOf(got).Elem(), reflect.ValueOf(42)); err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			want := newCompileRoot()
+			tt.want(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	t.Run("not assignable", func(t *testing.T) {
+		a, err := valpath.Compile(reflect.TypeFor[compileRoot](), valpath.Join(valpath.ExportedField("Items"), valpath.Index(0)))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		err = a.Set(reflect.ValueOf(newCompileRoot()).Elem(), reflect.ValueOf("x"))
+		if !errors.Is(err, valpath.ErrNotAssignable) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNotAssignable)
+		}
+	})
+}
+
+func TestAccessorAllocs(t *testing.T) {
+	a, err := valpath.Compile(
+		reflect.TypeFor[compileRoot](),
+		valpath.Join(valpath.ExportedField("Items"), valpath.Index(1), valpath.ExportedField("Int")))
+	if err != nil {
+		t.Fatalf("got error %v, want no error", err)
+	}
+	root := reflect.ValueOf(newCompileRoot()).Elem()
+	newVal := reflect.ValueOf(42)
+	if allocs := testing.AllocsPerRun(100, func() {
+		if _, err := a.Get(root); err != nil {
+			t.Fatal(err)
+		}
+	}); allocs != 0 {
+		t.Errorf("got %v allocations per Get, want 0", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() {
+		if err := a.Set(root, newVal); err != nil {
+			t.Fatal(err)
+		}
+	}); allocs != 0 {
+		t.Errorf("got %v allocations per Set, want 0", allocs)
+	}
+}
+
+var benchPath = valpath.Join(
+	valpath.ExportedField("Outer"), valpath.ExportedField("Int"))
+
+var benchSlicePath = valpath.Join(
+	valpath.ExportedField("Items"), valpath.Index(1), valpath.ExportedField("Int"))
+
+func BenchmarkTraverse(b *testing.B) {
+	root := reflect.ValueOf(newCompileRoot()).Elem()
+	for _, p := range []valpath.Path{benchPath, benchSlicePath} {
+		b.Run(p.String(), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := p.Traverse(root); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAccessorGet(b *testing.B) {
+	root := reflect.ValueOf(newCompileRoot()).Elem()
+	for _, p := range []valpath.Path{benchPath, benchSlicePath} {
+		a, err := valpath.Compile(root.Type(), p)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(p.String(), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := a.Get(root); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSet(b *testing.B) {
+	root := reflect.ValueOf(newCompileRoot()).Elem()
+	newVal := reflect.ValueOf(42)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := valpath.Set(root, benchSlicePath, newVal); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAccessorSet(b *testing.B) {
+	root := reflect.ValueOf(newCompileRoot()).Elem()
+	newVal := reflect.ValueOf(42)
+	a, err := valpath.Compile(root.Type(), benchSlicePath)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := a.Set(root, newVal); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
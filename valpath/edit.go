@@ -0,0 +1,170 @@
+package valpath
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+)
+
+var ErrUnsupportedStep = errors.New("operation is not supported at this step")
+
+// Delete removes the location addressed by p within root.  If the last step of p is an
+// Index or FromEnd step into a slice, the element is removed and later elements are shifted
+// down.  If it is a MapValueOfKey step, the map entry is removed.  If it is a field step, the
+// field is set to its zero value.  Intermediate values are copied and stored back as by Set.
+func Delete(root reflect.Value, p Path) error {
+	steps := slices.Collect(p.elems())
+	if len(steps) == 0 {
+		return newPathError(Empty(), root, ErrUnsupportedStep)
+	}
+	last := steps[len(steps)-1]
+	switch last.(type) {
+	case ExportedFieldPart, FieldIndexPart, TaggedFieldPart, UnexportedFieldPart:
+		return Set(root, p, zeroValue)
+	}
+	return editParent(root, steps, func(container reflect.Value) error {
+		switch last := last.(type) {
+		case IndexPart, FromEndPart:
+			i, err := sliceIndex(last, container, false)
+			if err != nil {
+				return err
+			}
+			if !container.CanSet() {
+				return ErrNotAddressable
+			}
+			n := container.Len()
+			reflect.Copy(container.Slice(i, n), container.Slice(i+1, n))
+			container.Index(n - 1).SetZero()
+			container.SetLen(n - 1)
+			return nil
+		case MapValueOfKeyPart:
+			key, err := entryKey(last, container)
+			if err != nil {
+				return err
+			}
+			if container.IsNil() || !container.MapIndex(key).IsValid() {
+				return ErrKeyNotFound
+			}
+			container.SetMapIndex(key, zeroValue)
+			return nil
+		default:
+			return ErrUnsupportedStep
+		}
+	})
+}
+
+// Insert adds v at the location addressed by p within root.  If the last step of p is an
+// Index or FromEnd step into a slice, v is inserted before the element at that position, and
+// Index may address the position just past the end to append.  If it is a MapValueOfKey
+// step, the map entry is added, replacing any existing entry with the same key, and a nil
+// map is made if it can be stored.  v is converted to the element type as by Set.
+func Insert(root reflect.Value, p Path, v reflect.Value) error {
+	steps := slices.Collect(p.elems())
+	if len(steps) == 0 {
+		return newPathError(Empty(), root, ErrUnsupportedStep)
+	}
+	last := steps[len(steps)-1]
+	return editParent(root, steps, func(container reflect.Value) error {
+		switch last := last.(type) {
+		case IndexPart, FromEndPart:
+			i, err := sliceIndex(last, container, true)
+			if err != nil {
+				return err
+			}
+			if !container.CanSet() {
+				return ErrNotAddressable
+			}
+			elem := reflect.New(container.Type().Elem()).Elem()
+			if err := assign(elem, v); err != nil {
+				return err
+			}
+			n := container.Len()
+			grown := reflect.Append(container, reflect.Zero(elem.Type()))
+			reflect.Copy(grown.Slice(i+1, n+1), grown.Slice(i, n))
+			grown.Index(i).Set(elem)
+			container.Set(grown)
+			return nil
+		case MapValueOfKeyPart:
+			key, err := entryKey(last, container)
+			if err != nil {
+				return err
+			}
+			elem := reflect.New(container.Type().Elem()).Elem()
+			if err := assign(elem, v); err != nil {
+				return err
+			}
+			if container.IsNil() {
+				if !container.CanSet() {
+					return ErrNilPointer
+				}
+				container.Set(reflect.MakeMap(container.Type()))
+			}
+			container.SetMapIndex(key, elem)
+			return nil
+		default:
+			return ErrUnsupportedStep
+		}
+	})
+}
+
+// editParent applies change to the value addressed by all but the last of steps, and then
+// stores the modified value back into root.  Errors returned by change are reported as
+// failures of the last step.
+func editParent(root reflect.Value, steps []Path, change func(container reflect.Value) error) error {
+	pos := len(steps) - 1
+	s, err := Join(steps[:pos]...).edit(root, &SetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := change(s.v); err != nil {
+		return atPos(err, steps[pos], typeOf(s.v), steps[:pos], pos)
+	}
+	if s.store != nil {
+		return s.store()
+	}
+	return nil
+}
+
+// sliceIndex returns the position within the slice v that step addresses.  If insert is
+// true, the position just past the end of v is also allowed.
+func sliceIndex(step Path, v reflect.Value, insert bool) (int, error) {
+	if !v.IsValid() {
+		return 0, ErrInvalidValue
+	}
+	if v.Kind() != reflect.Slice {
+		return 0, ErrKindMismatch
+	}
+	n := v.Len()
+	var i int
+	switch step := step.(type) {
+	case IndexPart:
+		i = int(step)
+	case FromEndPart:
+		if step < 1 {
+			return 0, ErrIndexOutOfRange
+		}
+		i = n - int(step)
+	}
+	limit := n
+	if insert {
+		limit++
+	}
+	if i < 0 || i >= limit {
+		return 0, ErrIndexOutOfRange
+	}
+	return i, nil
+}
+
+func entryKey(m MapValueOfKeyPart, v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() || !reflect.Value(m).IsValid() {
+		return zeroValue, ErrInvalidValue
+	}
+	if v.Kind() != reflect.Map {
+		return zeroValue, ErrKindMismatch
+	}
+	key, ok := mapKey(reflect.Value(m), v.Type().Key())
+	if !ok {
+		return zeroValue, ErrKindMismatch
+	}
+	return key, nil
+}
@@ -0,0 +1,213 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+func TestDelete(t *testing.T) {
+	testCases := []struct {
+		name    string
+		path    valpath.Path
+		want    func(*setRoot)
+		wantErr error
+	}{
+		{
+			name: "slice element",
+			path: valpath.Join(valpath.ExportedField("Slice"), valpath.Index(1)),
+			want: func(r *setRoot) { r.Slice = []int{1, 3} },
+		},
+		{
+			name: "last slice element from end",
+			path: valpath.Join(valpath.ExportedField("Slice"), valpath.FromEnd(1)),
+			want: func(r *setRoot) { r.Slice = []int{1, 2} },
+		},
+		{
+			name: "map entry",
+			path: valpath.Join(valpath.ExportedField("Map"), valpath.MapValueOfKey("a")),
+			want: func(r *setRoot) { r.Map = map[string]testtypes.Inner{} },
+		},
+		{
+			name: "nested map entry",
+			path: valpath.Join(valpath.ExportedField("Nested"), valpath.MapValueOfKey("a"), valpath.MapValueOfKey("b")),
+			want: func(r *setRoot) { r.Nested["a"] = map[string]int{} },
+		},
+		{
+			name: "struct field",
+			path: valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Int")),
+			want: func(r *setRoot) { r.Ptr.Int = 0 },
+		},
+		{
+			name:    "missing map entry",
+			path:    valpath.Join(valpath.ExportedField("Map"), valpath.MapValueOfKey("b")),
+			wantErr: valpath.ErrKeyNotFound,
+		},
+		{
+			name:    "nil map",
+			path:    valpath.Join(valpath.ExportedField("NilMap"), valpath.MapValueOfKey("a")),
+			wantErr: valpath.ErrKeyNotFound,
+		},
+		{
+			name:    "index out of range",
+			path:    valpath.Join(valpath.ExportedField("Slice"), valpath.Index(3)),
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+		{
+			name:    "array element",
+			path:    valpath.Join(valpath.ExportedField("Array"), valpath.Index(0)),
+			wantErr: valpath.ErrKindMismatch,
+		},
+		{
+			name:    "deref",
+			path:    valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref()),
+			wantErr: valpath.ErrUnsupportedStep,
+		},
+		{
+			name:    "empty path",
+			path:    valpath.Empty(),
+			wantErr: valpath.ErrUnsupportedStep,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.want == nil) == (tt.wantErr == nil) {
+				t.Fatal("exactly one of want and wantErr must be set")
+			}
+			got := newSetRoot()
+			err := valpath.Delete(reflect.ValueOf(got).Elem(), tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			want := newSetRoot()
+			tt.want(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestInsert(t *testing.T) {
+	testCases := []struct {
+		name    string
+		path    valpath.Path
+		newVal  any
+		want    func(*setRoot)
+		wantErr error
+	}{
+		{
+			name:   "slice start",
+			path:   valpath.Join(valpath.ExportedField("Slice"), valpath.Index(0)),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Slice = []int{42, 1, 2, 3} },
+		},
+		{
+			name:   "slice middle",
+			path:   valpath.Join(valpath.ExportedField("Slice"), valpath.Index(2)),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Slice = []int{1, 2, 42, 3} },
+		},
+		{
+			name:   "slice append",
+			path:   valpath.Join(valpath.ExportedField("Slice"), valpath.Index(3)),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Slice = []int{1, 2, 3, 42} },
+		},
+		{
+			name:   "before last from end",
+			path:   valpath.Join(valpath.ExportedField("Slice"), valpath.FromEnd(1)),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Slice = []int{1, 2, 42, 3} },
+		},
+		{
+			name:   "converted element",
+			path:   valpath.Join(valpath.ExportedField("Slice"), valpath.Index(0)),
+			newVal: int8(42),
+			want:   func(r *setRoot) { r.Slice = []int{42, 1, 2, 3} },
+		},
+		{
+			name:   "map entry",
+			path:   valpath.Join(valpath.ExportedField("Map"), valpath.MapValueOfKey("b")),
+			newVal: testtypes.Inner{Int: 42},
+			want:   func(r *setRoot) { r.Map["b"] = testtypes.Inner{Int: 42} },
+		},
+		{
+			name:   "replaced map entry",
+			path:   valpath.Join(valpath.ExportedField("Map"), valpath.MapValueOfKey("a")),
+			newVal: testtypes.Inner{Int: 42},
+			want:   func(r *setRoot) { r.Map["a"] = testtypes.Inner{Int: 42} },
+		},
+		{
+			name:   "nil map",
+			path:   valpath.Join(valpath.ExportedField("NilMap"), valpath.MapValueOfKey("a")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.NilMap = map[string]int{"a": 42} },
+		},
+		{
+			name:    "index out of range",
+			path:    valpath.Join(valpath.ExportedField("Slice"), valpath.Index(4)),
+			newVal:  42,
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+		{
+			name:    "not assignable",
+			path:    valpath.Join(valpath.ExportedField("Slice"), valpath.Index(0)),
+			newVal:  "forty-two",
+			wantErr: valpath.ErrNotAssignable,
+		},
+		{
+			name:    "struct field",
+			path:    valpath.ExportedField("Int"),
+			newVal:  42,
+			wantErr: valpath.ErrUnsupportedStep,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.want == nil) == (tt.wantErr == nil) {
+				t.Fatal("exactly one of want and wantErr must be set")
+			}
+			got := newSetRoot()
+			err := valpath.Insert(reflect.ValueOf(got).Elem(), tt.path, reflect.ValueOf(tt.newVal))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			want := newSetRoot()
+			tt.want(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestInsertDeleteInMap(t *testing.T) {
+	// Slices stored in maps aren't addressable, so they're copied and stored back.
+	lists := map[string][]int{"a": {1, 2}}
+	root := reflect.ValueOf(lists)
+	if err := valpath.Insert(root, valpath.Join(valpath.MapValueOfKey("a"), valpath.Index(2)), reflect.ValueOf(3)); err != nil {
+		t.Fatalf("got error %v, want no error", err)
+	}
+	if err := valpath.Delete(root, valpath.Join(valpath.MapValueOfKey("a"), valpath.Index(0))); err != nil {
+		t.Fatalf("got error %v, want no error", err)
+	}
+	if want := []int{2, 3}; !reflect.DeepEqual(lists["a"], want) {
+		t.Errorf("got %v, want %v", lists["a"], want)
+	}
+}
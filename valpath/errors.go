@@ -0,0 +1,98 @@
+package valpath
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+var (
+	ErrInvalidValue    = errors.New("invalid value")
+	ErrNilPointer      = errors.New("nil pointer or interface")
+	ErrKindMismatch    = errors.New("value has the wrong kind for this step")
+	ErrIndexOutOfRange = errors.New("index out of range")
+	ErrKeyNotFound     = errors.New("map key not found")
+	ErrNoSuchField     = errors.New("no such field")
+	ErrNoSuchMethod    = errors.New("no such getter method")
+	ErrUnexported      = errors.New("unexported field is read-only")
+	ErrTypeMismatch    = errors.New("value does not have the asserted type")
+)
+
+// errDynamic reports that a type can't be determined statically, because it depends on the
+// dynamic type of an interface value.
+var errDynamic = errors.New("type depends on the dynamic type of an interface value")
+
+// PathError describes a failure to apply a single step of a path.
+type PathError struct {
+	// Step is the step that failed.
+	Step Path
+	// Pos is the position of Step within the full path that was being traversed.
+	Pos int
+	// Prefix is the part of the path that was successfully traversed before Step.
+	Prefix Path
+	// Type is the type of the value that Step was applied to, or nil if that value was invalid.
+	Type reflect.Type
+	// Err is the underlying cause, typically one of the Err* sentinels in this package.
+	Err error
+}
+
+func (e *PathError) Error() string {
+	typeName := "<invalid>"
+	if e.Type != nil {
+		typeName = e.Type.String()
+	}
+	if _, ok := e.Prefix.(emptyPathElem); ok || e.Prefix == nil {
+		return fmt.Sprintf("valpath: step %d (%s) on %s: %v", e.Pos, e.Step, typeName, e.Err)
+	}
+	return fmt.Sprintf("valpath: step %d (%s) on %s after %s: %v", e.Pos, e.Step, typeName, e.Prefix, e.Err)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+func newPathError(step Path, v reflect.Value, err error) *PathError {
+	return newTypeError(step, typeOf(v), err)
+}
+
+func typeOf(v reflect.Value) reflect.Type {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Type()
+}
+
+func newTypeError(step Path, t reflect.Type, err error) *PathError {
+	return &PathError{
+		Step:   step,
+		Prefix: Empty(),
+		Type:   t,
+		Err:    err,
+	}
+}
+
+// atPos re-bases an error returned by a step at position pos of a longer path, whose
+// already-traversed steps are prefix.  t is the type the step was applied to.
+func atPos(err error, step Path, t reflect.Type, prefix []Path, pos int) error {
+	var pe *PathError
+	if !errors.As(err, &pe) {
+		pe = newTypeError(step, t, err)
+	}
+	rebased := *pe
+	rebased.Pos = pos + pe.Pos
+	rebased.Prefix = Join(append(prefix[:len(prefix):len(prefix)], pe.Prefix)...)
+	return &rebased
+}
+
+// leafError reports err as a failure of the last step of p, which produced a value of type t.
+func leafError(p Path, t reflect.Type, err error) *PathError {
+	steps := slices.Collect(p.elems())
+	pe := newTypeError(Empty(), t, err)
+	if n := len(steps); n > 0 {
+		pe.Step = steps[n-1]
+		pe.Pos = n - 1
+		pe.Prefix = Join(steps[:n-1]...)
+	}
+	return pe
+}
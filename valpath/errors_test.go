@@ -0,0 +1,109 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+func TestPathError(t *testing.T) {
+	type Deep struct {
+		Items []map[string]*testtypes.Inner
+	}
+	in := reflect.ValueOf(Deep{
+		Items: []map[string]*testtypes.Inner{
+			{"present": {Int: 42}, "nil": nil},
+		},
+	})
+	testCases := []struct {
+		name       string
+		path       valpath.Path
+		wantErr    error
+		wantPos    int
+		wantPrefix string
+		wantType   reflect.Type
+	}{
+		{
+			name:       "index out of range",
+			path:       valpath.Join(valpath.ExportedField("Items"), valpath.Index(1)),
+			wantErr:    valpath.ErrIndexOutOfRange,
+			wantPos:    1,
+			wantPrefix: valpath.ExportedField("Items").String(),
+			wantType:   reflect.TypeFor[[]map[string]*testtypes.Inner](),
+		},
+		{
+			name:       "missing map key",
+			path:       valpath.Join(valpath.ExportedField("Items"), valpath.Index(0), valpath.MapValueOfKey("absent")),
+			wantErr:    valpath.ErrKeyNotFound,
+			wantPos:    2,
+			wantPrefix: valpath.Join(valpath.ExportedField("Items"), valpath.Index(0)).String(),
+			wantType:   reflect.TypeFor[map[string]*testtypes.Inner](),
+		},
+		{
+			name: "nil pointer",
+			path: valpath.Join(
+				valpath.ExportedField("Items"), valpath.Index(0), valpath.MapValueOfKey("nil"),
+				valpath.Deref(), valpath.ExportedField("Int")),
+			wantErr:    valpath.ErrNilPointer,
+			wantPos:    3,
+			wantPrefix: valpath.Join(valpath.ExportedField("Items"), valpath.Index(0), valpath.MapValueOfKey("nil")).String(),
+			wantType:   reflect.TypeFor[*testtypes.Inner](),
+		},
+		{
+			name: "no such field",
+			path: valpath.Join(
+				valpath.ExportedField("Items"), valpath.Index(0), valpath.MapValueOfKey("present"),
+				valpath.Deref(), valpath.ExportedField("Missing")),
+			wantErr:    valpath.ErrNoSuchField,
+			wantPos:    4,
+			wantPrefix: valpath.Join(valpath.ExportedField("Items"), valpath.Index(0), valpath.MapValueOfKey("present"), valpath.Deref()).String(),
+			wantType:   reflect.TypeFor[testtypes.Inner](),
+		},
+		{
+			name:       "kind mismatch",
+			path:       valpath.Join(valpath.ExportedField("Items"), valpath.Deref()),
+			wantErr:    valpath.ErrKindMismatch,
+			wantPos:    1,
+			wantPrefix: valpath.ExportedField("Items").String(),
+			wantType:   reflect.TypeFor[[]map[string]*testtypes.Inner](),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.path.Traverse(in)
+			if got.IsValid() {
+				t.Errorf("got value %v, want invalid value", got)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			var pathErr *valpath.PathError
+			if !errors.As(err, &pathErr) {
+				t.Fatalf("got error of type %T, want *valpath.PathError", err)
+			}
+			if pathErr.Pos != tt.wantPos {
+				t.Errorf("got pos %d, want %d", pathErr.Pos, tt.wantPos)
+			}
+			if got := pathErr.Prefix.String(); got != tt.wantPrefix {
+				t.Errorf("got prefix %q, want %q", got, tt.wantPrefix)
+			}
+			if pathErr.Type != tt.wantType {
+				t.Errorf("got type %v, want %v", pathErr.Type, tt.wantType)
+			}
+		})
+	}
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := valpath.Deref().Traverse(reflect.Value{})
+		if !errors.Is(err, valpath.ErrInvalidValue) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrInvalidValue)
+		}
+		var pathErr *valpath.PathError
+		if errors.As(err, &pathErr) && pathErr.Type != nil {
+			t.Errorf("got type %v, want nil", pathErr.Type)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package valpath
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+var ErrNoGoEquivalent = errors.New("step has no Go expression equivalent")
+
+// GoExpr renders p as a Go expression that evaluates to the location p addresses within the
+// operand named by rootName, such as (*x.Ptr).Items[3] or x.M["key"].  FromEnd steps use
+// len, string map keys are quoted with strconv.Quote, and other map keys are written with
+// %#v.  Inter, MapKey, FieldIndex and TaggedField steps have no equivalent that can be
+// written without knowing the types involved.
+func GoExpr(rootName string, p Path) (string, error) {
+	f := &formatter{expr: rootName}
+	for elem := range p.elems() {
+		if err := goStep(f, elem); err != nil {
+			return "", fmt.Errorf("%w: %s", err, elem)
+		}
+	}
+	return f.expr, nil
+}
+
+func goStep(f *formatter, step Path) error {
+	switch step := step.(type) {
+	case emptyPathElem:
+	case ExportedFieldPart:
+		f.postfix("." + string(step))
+	case UnexportedFieldPart:
+		f.postfix("." + string(step))
+	case MethodPart:
+		f.postfix("." + string(step) + "()")
+	case IndexPart:
+		f.postfix(fmt.Sprintf("[%d]", step))
+	case FromEndPart:
+		f.postfix(fmt.Sprintf("[len(%s)-%d]", f.expr, step))
+	case SliceRangePart:
+		f.postfix(fmt.Sprintf("[%d:%d]", step.Lo, step.Hi))
+	case ByteAtPart:
+		f.postfix(fmt.Sprintf("[%d]", step))
+	case RuneAtPart:
+		f.conversion("[]rune", fmt.Sprintf("[%d]", step))
+	case MapValueOfKeyPart:
+		key := unwrapKey(reflect.Value(step))
+		if !key.IsValid() {
+			return ErrNoGoEquivalent
+		}
+		switch key.Kind() {
+		case reflect.String:
+			f.postfix("[" + strconv.Quote(key.String()) + "]")
+		case reflect.Interface:
+			f.postfix("[nil]")
+		default:
+			f.postfix(fmt.Sprintf("[%#v]", key.Interface()))
+		}
+	case DerefPart:
+		f.prefix("*")
+	case AddrPart:
+		f.prefix("&")
+	case AsTypePart:
+		f.postfix(".(" + step.Type.String() + ")")
+	default:
+		return ErrNoGoEquivalent
+	}
+	return nil
+}
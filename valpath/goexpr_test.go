@@ -0,0 +1,127 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+func TestGoExpr(t *testing.T) {
+	testCases := []struct {
+		name    string
+		path    valpath.Path
+		want    string
+		wantErr error
+	}{
+		{
+			name: "empty",
+			path: valpath.Empty(),
+			want: "x",
+		},
+		{
+			name: "fields",
+			path: valpath.Join(valpath.ExportedField("Inner"), valpath.ExportedField("Int")),
+			want: "x.Inner.Int",
+		},
+		{
+			name: "deref",
+			path: valpath.Join(valpath.ExportedField("Inner"), valpath.Deref(), valpath.ExportedField("Int")),
+			want: "(*x.Inner).Int",
+		},
+		{
+			name: "trailing deref",
+			path: valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref()),
+			want: "*x.Ptr",
+		},
+		{
+			name: "addr",
+			path: valpath.Join(valpath.ExportedField("Inner"), valpath.Addr()),
+			want: "&x.Inner",
+		},
+		{
+			name: "index",
+			path: valpath.Join(valpath.ExportedField("Items"), valpath.Index(3)),
+			want: "x.Items[3]",
+		},
+		{
+			name: "from end",
+			path: valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.FromEnd(1)),
+			want: "(*x.Ptr)[len(*x.Ptr)-1]",
+		},
+		{
+			name: "slice range",
+			path: valpath.Join(valpath.ExportedField("Items"), valpath.SliceRange(1, 3)),
+			want: "x.Items[1:3]",
+		},
+		{
+			name: "byte and rune",
+			path: valpath.Join(valpath.ExportedField("Names"), valpath.Index(0), valpath.RuneAt(2)),
+			want: "[]rune(x.Names[0])[2]",
+		},
+		{
+			name: "string byte",
+			path: valpath.Join(valpath.ExportedField("Name"), valpath.ByteAt(2)),
+			want: "x.Name[2]",
+		},
+		{
+			name: "string map key",
+			path: valpath.Join(valpath.ExportedField("M"), valpath.MapValueOfKey("key\n")),
+			want: `x.M["key\n"]`,
+		},
+		{
+			name: "int map key",
+			path: valpath.Join(valpath.ExportedField("M"), valpath.MapValueOfKey(int64(7))),
+			want: "x.M[7]",
+		},
+		{
+			name: "struct map key",
+			path: valpath.Join(valpath.ExportedField("M"), valpath.MapValueOfKey(testtypes.Inner{Int: 1})),
+			want: "x.M[testtypes.Inner{Int:1}]",
+		},
+		{
+			name: "type assertion",
+			path: valpath.Join(valpath.ExportedField("Any"), valpath.AsType(reflect.TypeFor[testtypes.IFace]())),
+			want: "x.Any.(testtypes.IFace)",
+		},
+		{
+			name: "method and unexported field",
+			path: valpath.Join(valpath.ExportedField("IFace"), valpath.Method("String"), valpath.UnexportedField("count")),
+			want: "x.IFace.String().count",
+		},
+		{
+			name:    "inter",
+			path:    valpath.Join(valpath.ExportedField("Any"), valpath.Inter()),
+			wantErr: valpath.ErrNoGoEquivalent,
+		},
+		{
+			name:    "map key step",
+			path:    valpath.Join(valpath.ExportedField("M"), valpath.MapKey("a")),
+			wantErr: valpath.ErrNoGoEquivalent,
+		},
+		{
+			name:    "field index",
+			path:    valpath.FieldIndex([]int{0}),
+			wantErr: valpath.ErrNoGoEquivalent,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valpath.GoExpr("x", tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package valpath
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSON Pointers (RFC 6901) address locations within the JSON encoding of a value, such as
+// /items/3/name.  Converting between them and paths needs the Go type being encoded, both to
+// map json struct tags to fields and because JSON has no equivalent of Deref or Inter steps.
+
+var ErrNoJSONEquivalent = errors.New("step has no JSON Pointer equivalent")
+
+// FromJSONPointer builds a Path addressing the location that ptr refers to within the JSON
+// encoding of values of type t.  Object members are matched to struct fields by the names
+// that encoding/json would use, to map keys with string or integer types, and array elements
+// to Index steps.  Deref steps are inserted where pointers are encoded transparently.  A
+// pointer can't continue past an interface, since the type that the interface holds isn't
+// known; FromJSONPointerValue can.
+func FromJSONPointer(t reflect.Type, ptr string) (Path, error) {
+	return fromJSONPointer(t, zeroValue, ptr)
+}
+
+// FromJSONPointerValue is like FromJSONPointer, but resolves ptr within the value v, so that
+// Inter steps are inserted for interfaces and the tokens after them are matched against the
+// types of the values they hold, as with a map[string]any.  Every location that ptr passes
+// through must exist in v, though the last token may name a map key or struct field whose
+// value is yet to be set.
+func FromJSONPointerValue(v reflect.Value, ptr string) (Path, error) {
+	if !v.IsValid() {
+		return nil, newPathError(Empty(), v, ErrInvalidValue)
+	}
+	return fromJSONPointer(v.Type(), v, ptr)
+}
+
+// fromJSONPointer converts ptr for values of type t.  If v is valid, it is the value that the
+// converted steps are applied to, and is used to look inside interfaces.
+func fromJSONPointer(t reflect.Type, v reflect.Value, ptr string) (Path, error) {
+	if ptr == "" {
+		return Empty(), nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("%w: JSON Pointer %q does not start with '/'", ErrSyntax, ptr)
+	}
+	if t == nil {
+		return nil, newTypeError(Empty(), t, ErrInvalidValue)
+	}
+	var steps []Path
+	// apply appends step, and moves t, and v if a value is being followed, past it.
+	apply := func(step Path) error {
+		var next reflect.Type
+		var err error
+		if v.IsValid() {
+			v, err = step.Traverse(v)
+			next = typeOf(v)
+		} else {
+			next, err = step.resolveType(t)
+		}
+		if err != nil {
+			return atPos(err, step, t, steps, len(steps))
+		}
+		steps = append(steps, step)
+		t = next
+		return nil
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for n, token := range tokens {
+		token, err := unescapeJSONToken(token)
+		if err != nil {
+			return nil, err
+		}
+		for t.Kind() == reflect.Pointer || (t.Kind() == reflect.Interface && v.IsValid()) {
+			if t.Kind() == reflect.Pointer {
+				err = apply(Deref())
+			} else {
+				err = apply(Inter())
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		fail := func(step Path, err error) (Path, error) {
+			err = fmt.Errorf("JSON Pointer token %q: %w", token, err)
+			return nil, atPos(err, step, t, steps, len(steps))
+		}
+		var step []Path
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := taggedFieldByName(t, "json", token)
+			if !ok {
+				return fail(TaggedField("json", token), ErrNoSuchField)
+			}
+			step = explicitField(t, field.index, false)
+		case reflect.Slice, reflect.Array:
+			i, err := parseJSONIndex(token)
+			if err != nil {
+				return fail(Empty(), fmt.Errorf("%w: %w", ErrIndexOutOfRange, err))
+			}
+			if t.Kind() == reflect.Array && i >= t.Len() {
+				return fail(Index(i), ErrIndexOutOfRange)
+			}
+			step = []Path{Index(i)}
+		case reflect.Map:
+			key, err := parseJSONKey(token, t.Key())
+			if err != nil {
+				return fail(MapValueOfKey(token), err)
+			}
+			step = []Path{MapValueOfKeyPart(key)}
+		case reflect.Interface:
+			return fail(Inter(), errDynamic)
+		default:
+			return fail(Empty(), ErrKindMismatch)
+		}
+		if n == len(tokens)-1 {
+			// The last location needn't exist yet, so it isn't traversed.
+			v = zeroValue
+		}
+		for _, s := range step {
+			if err := apply(s); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return Join(steps...), nil
+}
+
+func unescapeJSONToken(token string) (string, error) {
+	if !strings.Contains(token, "~") {
+		return token, nil
+	}
+	b := &strings.Builder{}
+	for i := 0; i < len(token); i++ {
+		if token[i] != '~' {
+			b.WriteByte(token[i])
+			continue
+		}
+		i++
+		switch {
+		case i < len(token) && token[i] == '0':
+			b.WriteByte('~')
+		case i < len(token) && token[i] == '1':
+			b.WriteByte('/')
+		default:
+			return "", fmt.Errorf("%w: invalid escape in JSON Pointer token %q", ErrSyntax, token)
+		}
+	}
+	return b.String(), nil
+}
+
+// parseJSONIndex parses an array index, which RFC 6901 requires to be written without
+// leading zeros.  The "-" token, which refers past the end of an array, is rejected.
+func parseJSONIndex(token string) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return strconv.Atoi(token)
+}
+
+func parseJSONKey(token string, t reflect.Type) (reflect.Value, error) {
+	key := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		key.SetString(token)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(token, 10, t.Bits())
+		if err != nil {
+			return zeroValue, ErrKeyNotFound
+		}
+		key.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(token, 10, t.Bits())
+		if err != nil {
+			return zeroValue, ErrKeyNotFound
+		}
+		key.SetUint(u)
+	default:
+		return zeroValue, ErrKindMismatch
+	}
+	return key, nil
+}
+
+// ToJSONPointer renders p, which is meant to be applied to values of type t, as a JSON
+// Pointer.  Deref and Inter steps are dropped, and struct fields are named as encoding/json
+// would name them.  Fields that aren't encoded, embedded structs whose fields are promoted
+// into their parent's object, and steps like Method with no counterpart in JSON can't be
+// rendered.
+func ToJSONPointer(t reflect.Type, p Path) (string, error) {
+	b := &strings.Builder{}
+	steps := slices.Collect(p.elems())
+	// While stepping through embedded structs, object is the struct whose JSON object is
+	// being addressed, and index leads from it to the current struct.
+	var object reflect.Type
+	var index []int
+	// applied is the type that the latest step was applied to.
+	var applied reflect.Type
+	for pos, step := range steps {
+		applied = t
+		next, err := step.resolveType(t)
+		dynamic := errors.Is(err, errDynamic)
+		if err != nil && !dynamic && t != nil {
+			return "", atPos(err, step, t, steps[:pos], pos)
+		}
+		fail := func(err error) (string, error) {
+			return "", atPos(err, step, t, steps[:pos], pos)
+		}
+
+		var token string
+		switch step := step.(type) {
+		case DerefPart, InterPart:
+			t = next
+			continue
+		case ExportedFieldPart, FieldIndexPart, TaggedFieldPart:
+			if t == nil {
+				return fail(errDynamic)
+			}
+			if object == nil {
+				object = t
+				index = nil
+			}
+			switch step := step.(type) {
+			case ExportedFieldPart:
+				fieldDesc, _ := t.FieldByName(string(step))
+				index = append(index, fieldDesc.Index...)
+			case FieldIndexPart:
+				index = append(index, step...)
+			case TaggedFieldPart:
+				index = append(index, step.index(t)...)
+			}
+			field, ok := taggedFieldByIndex(object, "json", index)
+			if !ok {
+				if !tagEmbeds(object, "json", index) {
+					return fail(ErrNoJSONEquivalent)
+				}
+				t = next
+				continue
+			}
+			token = field.name
+			object = nil
+		case IndexPart:
+			if object != nil {
+				return fail(ErrNoJSONEquivalent)
+			}
+			token = strconv.Itoa(int(step))
+		case MapValueOfKeyPart:
+			if object != nil {
+				return fail(ErrNoJSONEquivalent)
+			}
+			key := reflect.Value(step)
+			switch key.Kind() {
+			case reflect.String:
+				token = key.String()
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				token = strconv.FormatInt(key.Int(), 10)
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				token = strconv.FormatUint(key.Uint(), 10)
+			default:
+				return fail(ErrNoJSONEquivalent)
+			}
+		default:
+			return fail(ErrNoJSONEquivalent)
+		}
+		b.WriteString("/")
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+		t = next
+	}
+	if object != nil {
+		pos := len(steps) - 1
+		return "", atPos(ErrNoJSONEquivalent, steps[pos], applied, steps[:pos], pos)
+	}
+	return b.String(), nil
+}
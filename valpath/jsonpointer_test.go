@@ -0,0 +1,330 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+type jsonItem struct {
+	Name  string `json:"name"`
+	Skip  int    `json:"-"`
+	Plain int
+	Count int `json:"count,omitempty"`
+}
+
+type jsonBase struct {
+	ID string `json:"id"`
+}
+
+type jsonRoot struct {
+	jsonBase
+	*testtypes.Inner
+	Items  []jsonItem          `json:"items"`
+	Ptr    *jsonItem           `json:"ptr"`
+	Tags   map[string]int      `json:"tags"`
+	ByID   map[int64]*jsonItem `json:"by_id"`
+	Odd    jsonItem            `json:"a/b~c"`
+	Any    any                 `json:"any"`
+	hidden int
+}
+
+func TestJSONPointer(t *testing.T) {
+	root := reflect.TypeFor[jsonRoot]()
+	testCases := []struct {
+		name string
+		ptr  string
+		want valpath.Path
+	}{
+		{
+			name: "root",
+			ptr:  "",
+			want: valpath.Empty(),
+		},
+		{
+			name: "tagged fields",
+			ptr:  "/items/3/name",
+			want: valpath.Join(valpath.ExportedField("Items"), valpath.Index(3), valpath.ExportedField("Name")),
+		},
+		{
+			name: "untagged field",
+			ptr:  "/items/0/Plain",
+			want: valpath.Join(valpath.ExportedField("Items"), valpath.Index(0), valpath.ExportedField("Plain")),
+		},
+		{
+			name: "tag with options",
+			ptr:  "/items/0/count",
+			want: valpath.Join(valpath.ExportedField("Items"), valpath.Index(0), valpath.ExportedField("Count")),
+		},
+		{
+			name: "implicit deref",
+			ptr:  "/ptr/name",
+			want: valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Name")),
+		},
+		{
+			name: "promoted from unexported embedded struct",
+			ptr:  "/id",
+			want: valpath.FieldIndex([]int{0, 0}),
+		},
+		{
+			name: "promoted through embedded pointer",
+			ptr:  "/Int",
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name: "string map key",
+			ptr:  "/tags/env",
+			want: valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey("env")),
+		},
+		{
+			name: "integer map key",
+			ptr:  "/by_id/-7/name",
+			want: valpath.Join(valpath.ExportedField("ByID"), valpath.MapValueOfKey(int64(-7)), valpath.Deref(), valpath.ExportedField("Name")),
+		},
+		{
+			name: "escaped tokens",
+			ptr:  "/a~1b~0c/name",
+			want: valpath.Join(valpath.ExportedField("Odd"), valpath.ExportedField("Name")),
+		},
+		{
+			name: "escaped map key",
+			ptr:  "/tags/~01~1",
+			want: valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey("~1/")),
+		},
+		{
+			name: "interface leaf",
+			ptr:  "/any",
+			want: valpath.ExportedField("Any"),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valpath.FromJSONPointer(root, tt.ptr)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !valpath.Equal(got, tt.want) {
+				t.Errorf("got path %s, want %s", got, tt.want)
+			}
+			ptr, err := valpath.ToJSONPointer(root, got)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if ptr != tt.ptr {
+				t.Errorf("got pointer %q, want %q", ptr, tt.ptr)
+			}
+		})
+	}
+}
+
+func TestFromJSONPointerErrors(t *testing.T) {
+	root := reflect.TypeFor[jsonRoot]()
+	testCases := []struct {
+		name    string
+		ptr     string
+		wantErr error
+	}{
+		{name: "missing slash", ptr: "items", wantErr: valpath.ErrSyntax},
+		{name: "bad escape", ptr: "/a~2b", wantErr: valpath.ErrSyntax},
+		{name: "trailing tilde", ptr: "/a~", wantErr: valpath.ErrSyntax},
+		{name: "unknown member", ptr: "/nope", wantErr: valpath.ErrNoSuchField},
+		{name: "ignored field", ptr: "/items/0/Skip", wantErr: valpath.ErrNoSuchField},
+		{name: "go name of tagged field", ptr: "/Items", wantErr: valpath.ErrNoSuchField},
+		{name: "unexported field", ptr: "/hidden", wantErr: valpath.ErrNoSuchField},
+		{name: "leading zero", ptr: "/items/01", wantErr: valpath.ErrIndexOutOfRange},
+		{name: "end of array", ptr: "/items/-", wantErr: valpath.ErrIndexOutOfRange},
+		{name: "bad integer key", ptr: "/by_id/x", wantErr: valpath.ErrKeyNotFound},
+		{name: "into scalar", ptr: "/items/0/name/x", wantErr: valpath.ErrKindMismatch},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := valpath.FromJSONPointer(root, tt.ptr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("through interface", func(t *testing.T) {
+		if _, err := valpath.FromJSONPointer(root, "/any/x"); err == nil {
+			t.Error("got no error, want error")
+		}
+	})
+
+	t.Run("path error", func(t *testing.T) {
+		_, err := valpath.FromJSONPointer(root, "/items/0/nope")
+		var pathErr *valpath.PathError
+		if !errors.As(err, &pathErr) {
+			t.Fatalf("got error %v, want a PathError", err)
+		}
+		wantPrefix := valpath.Join(valpath.ExportedField("Items"), valpath.Index(0))
+		if pathErr.Pos != 2 || !valpath.Equal(pathErr.Prefix, wantPrefix) || pathErr.Type != reflect.TypeFor[jsonItem]() {
+			t.Errorf("got error at step %d after %v on %v, want step 2 after %v on jsonItem", pathErr.Pos, pathErr.Prefix, pathErr.Type, wantPrefix)
+		}
+	})
+}
+
+func TestFromJSONPointerValue(t *testing.T) {
+	doc := map[string]any{
+		"a":    map[string]any{"b": []any{1, "two"}},
+		"item": &jsonItem{Name: "n"},
+		"nil":  nil,
+	}
+	root := reflect.ValueOf(doc)
+	testCases := []struct {
+		name string
+		ptr  string
+		want valpath.Path
+		// absent is true if the location doesn't exist yet.
+		absent  bool
+		wantErr error
+	}{
+		{
+			name: "nested maps",
+			ptr:  "/a/b/1",
+			want: valpath.Join(valpath.MapValueOfKey("a"), valpath.Inter(), valpath.MapValueOfKey("b"), valpath.Inter(), valpath.Index(1)),
+		},
+		{
+			name: "struct behind interface and pointer",
+			ptr:  "/item/name",
+			want: valpath.Join(valpath.MapValueOfKey("item"), valpath.Inter(), valpath.Deref(), valpath.ExportedField("Name")),
+		},
+		{
+			name:   "new member",
+			ptr:    "/a/c",
+			want:   valpath.Join(valpath.MapValueOfKey("a"), valpath.Inter(), valpath.MapValueOfKey("c")),
+			absent: true,
+		},
+		{
+			name:    "missing member on the way",
+			ptr:     "/x/y",
+			wantErr: valpath.ErrKeyNotFound,
+		},
+		{
+			name:    "nil interface",
+			ptr:     "/nil/y",
+			wantErr: valpath.ErrNilPointer,
+		},
+		{
+			name:    "index out of range on the way",
+			ptr:     "/a/b/2/x",
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valpath.FromJSONPointerValue(root, tt.ptr)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !valpath.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if !tt.absent {
+				if _, err := got.Traverse(root); err != nil {
+					t.Errorf("got traverse error %v, want no error", err)
+				}
+			}
+		})
+	}
+}
+
+func TestToJSONPointer(t *testing.T) {
+	root := reflect.TypeFor[jsonRoot]()
+	testCases := []struct {
+		name    string
+		path    valpath.Path
+		want    string
+		wantErr error
+		wantPos int
+	}{
+		{
+			name: "promoted field",
+			path: valpath.ExportedField("ID"),
+			want: "/id",
+		},
+		{
+			name: "promoted through pointer",
+			path: valpath.ExportedField("Int"),
+			want: "/Int",
+		},
+		{
+			name: "field index",
+			path: valpath.FieldIndex([]int{2}),
+			want: "/items",
+		},
+		{
+			name: "after interface",
+			path: valpath.Join(valpath.ExportedField("Any"), valpath.Inter(), valpath.Index(2)),
+			want: "/any/2",
+		},
+		{
+			name:    "ignored field",
+			path:    valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Skip")),
+			wantErr: valpath.ErrNoJSONEquivalent,
+			wantPos: 2,
+		},
+		{
+			name:    "embedded struct itself",
+			path:    valpath.Join(valpath.ExportedField("Inner"), valpath.Deref()),
+			wantErr: valpath.ErrNoJSONEquivalent,
+			wantPos: 1,
+		},
+		{
+			name:    "unexported field",
+			path:    valpath.UnexportedField("hidden"),
+			wantErr: valpath.ErrNoJSONEquivalent,
+			wantPos: 0,
+		},
+		{
+			name:    "map key",
+			path:    valpath.Join(valpath.ExportedField("Tags"), valpath.MapKey("env")),
+			wantErr: valpath.ErrNoJSONEquivalent,
+			wantPos: 1,
+		},
+		{
+			name:    "invalid path",
+			path:    valpath.ExportedField("Nope"),
+			wantErr: valpath.ErrNoSuchField,
+			wantPos: 0,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valpath.ToJSONPointer(root, tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				var pathErr *valpath.PathError
+				if !errors.As(err, &pathErr) || pathErr.Pos != tt.wantPos {
+					t.Errorf("got error %v, want error at step %d", err, tt.wantPos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("field after interface", func(t *testing.T) {
+		_, err := valpath.ToJSONPointer(root, valpath.Join(valpath.ExportedField("Any"), valpath.Inter(), valpath.ExportedField("Name")))
+		var pathErr *valpath.PathError
+		if !errors.As(err, &pathErr) || pathErr.Pos != 2 {
+			t.Errorf("got error %v, want error at step 2", err)
+		}
+	})
+}
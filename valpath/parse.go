@@ -0,0 +1,538 @@
+package valpath
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The textual syntax for paths follows Go's selector and index expressions, with the root
+// value left implicit:
+//
+//	Inner.Int       ExportedField("Inner"), ExportedField("Int")
+//	Items[3]        ExportedField("Items"), Index(3)
+//	Items[-1]       ExportedField("Items"), FromEnd(1)
+//	Items[1:3]      ExportedField("Items"), SliceRange(1, 3)
+//	[]byte(Name)[2] ExportedField("Name"), ByteAt(2)
+//	[]rune(Name)[2] ExportedField("Name"), RuneAt(2)
+//	Tags["env"]     ExportedField("Tags"), MapValueOfKey("env")
+//	M[int64(7)]     ExportedField("M"), MapValueOfKey(int64(7))
+//	Tags{"env"}     ExportedField("Tags"), MapKey("env")
+//	*Ptr            ExportedField("Ptr"), Deref()
+//	(*Ptr).Int      ExportedField("Ptr"), Deref(), ExportedField("Int")
+//	&Inner          ExportedField("Inner"), Addr()
+//	(IFace).Name    ExportedField("IFace"), Inter(), ExportedField("Name")
+//	Any.(int)       ExportedField("Any"), As[int]()
+//	IFace.String()  ExportedField("IFace"), Method("String")
+//	Inner.~count    ExportedField("Inner"), UnexportedField("count")
+//	Inner.#0,1      ExportedField("Inner"), FieldIndex([]int{0, 1})
+//
+// As in Go, a leading '*' or '&' applies to the whole selector expression that follows it,
+// so parentheses are needed to continue past a dereference or address.  Parentheses around
+// any other expression unwrap the interface it yields, so a dereferenced interface is written
+// ((*Ptr)), and the root interface is ().  Map keys may be strings, bools, or numbers
+// converted to a predeclared type, such as int(3) or float64(1.5), and type assertions are
+// likewise limited to predeclared types.
+//
+// With SyntaxOptions.TagKey set to "json", field names are instead the names given by json
+// struct tags, so that items[0].display_name is
+//
+//	TaggedField("json", "items"), Index(0), TaggedField("json", "display_name")
+
+var (
+	ErrSyntax         = errors.New("invalid path syntax")
+	ErrNotFormattable = errors.New("path cannot be expressed in the textual syntax")
+)
+
+// formattableKeyType maps the names of predeclared types that may be used for map keys to
+// the types themselves.
+var formattableKeyType = map[string]reflect.Type{}
+
+func init() {
+	for _, t := range []reflect.Type{
+		reflect.TypeFor[bool](),
+		reflect.TypeFor[string](),
+		reflect.TypeFor[int](),
+		reflect.TypeFor[int8](),
+		reflect.TypeFor[int16](),
+		reflect.TypeFor[int32](),
+		reflect.TypeFor[int64](),
+		reflect.TypeFor[uint](),
+		reflect.TypeFor[uint8](),
+		reflect.TypeFor[uint16](),
+		reflect.TypeFor[uint32](),
+		reflect.TypeFor[uint64](),
+		reflect.TypeFor[uintptr](),
+		reflect.TypeFor[float32](),
+		reflect.TypeFor[float64](),
+	} {
+		formattableKeyType[t.Name()] = t
+	}
+}
+
+// SyntaxOptions controls the textual syntax used by ParseWith and FormatWith.
+type SyntaxOptions struct {
+	// TagKey, if set, makes field names refer to fields by the names given to them by that
+	// struct tag, as with TaggedField, rather than by their Go names.
+	TagKey string
+}
+
+// Format renders p in the textual syntax accepted by Parse.
+func Format(p Path) (string, error) {
+	return FormatWith(p, SyntaxOptions{})
+}
+
+// FormatWith is like Format, but with the syntax controlled by opts.
+func FormatWith(p Path, opts SyntaxOptions) (string, error) {
+	f := &formatter{tagKey: opts.TagKey}
+	for elem := range p.elems() {
+		if err := elem.format(f); err != nil {
+			return "", fmt.Errorf("%w: %s: %w", ErrNotFormattable, elem, err)
+		}
+	}
+	return f.expr, nil
+}
+
+type formatter struct {
+	tagKey string
+	expr   string
+	// deref is true when expr begins with a '*' or '&' that must be parenthesized before a
+	// selector or index can be appended.
+	deref bool
+}
+
+func (f *formatter) postfix(s string) {
+	if f.deref {
+		f.expr = "(" + f.expr + ")"
+		f.deref = false
+	}
+	f.expr += s
+}
+
+// paren wraps expr in parentheses to unwrap an interface.  A leading '*' or '&' is first
+// parenthesized by itself, since parentheses around it only group.
+func (f *formatter) paren() {
+	f.postfix("")
+	f.expr = "(" + f.expr + ")"
+}
+
+func (f *formatter) selector(name string) {
+	if f.expr == "" {
+		f.postfix(name)
+	} else {
+		f.postfix("." + name)
+	}
+}
+
+// conversion wraps expr in a conversion to the type named by conv, and then appends s.
+func (f *formatter) conversion(conv, s string) {
+	f.expr = conv + "(" + f.expr + ")" + s
+	f.deref = false
+}
+
+func (f *formatter) prefix(s string) {
+	f.expr = s + f.expr
+	f.deref = true
+}
+
+func formatIdent(name string) (string, error) {
+	if !isIdent(name) {
+		return "", fmt.Errorf("%q is not an identifier", name)
+	}
+	return name, nil
+}
+
+func formatKey(k reflect.Value) (string, error) {
+	if !k.IsValid() {
+		return "", errors.New("invalid map key")
+	}
+	t := k.Type()
+	if formattableKeyType[t.Name()] != t {
+		return "", fmt.Errorf("map key type %s is not a predeclared type", t)
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return strconv.Quote(k.String()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(k.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("%s(%d)", t.Name(), k.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return fmt.Sprintf("%s(%d)", t.Name(), k.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%s(%s)", t.Name(), strconv.FormatFloat(k.Float(), 'g', -1, t.Bits())), nil
+	default:
+		return "", fmt.Errorf("map key type %s is not supported", t)
+	}
+}
+
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !isIdentRune(r, i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentRune(r rune, first bool) bool {
+	return r == '_' || unicode.IsLetter(r) || (!first && unicode.IsDigit(r))
+}
+
+// Parse builds a Path from the textual syntax produced by Format.
+func Parse(s string) (Path, error) {
+	return ParseWith(s, SyntaxOptions{})
+}
+
+// ParseWith is like Parse, but with the syntax controlled by opts.
+func ParseWith(s string, opts SyntaxOptions) (Path, error) {
+	p := &parser{in: s, tagKey: opts.TagKey}
+	path, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.errorf("unexpected %q", p.rest())
+	}
+	return path, nil
+}
+
+type parser struct {
+	in     string
+	pos    int
+	tagKey string
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w at offset %d: %s", ErrSyntax, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.in)
+}
+
+func (p *parser) rest() string {
+	return p.in[p.pos:]
+}
+
+func (p *parser) peek(s string) bool {
+	return strings.HasPrefix(p.rest(), s)
+}
+
+func (p *parser) accept(s string) bool {
+	if p.peek(s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.accept(s) {
+		return p.errorf("expected %q", s)
+	}
+	return nil
+}
+
+func (p *parser) expr() (Path, error) {
+	if p.accept("*") {
+		inner, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return Join(inner, Deref()), nil
+	}
+	if p.accept("&") {
+		inner, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return Join(inner, Addr()), nil
+	}
+	return p.postfix()
+}
+
+func (p *parser) postfix() (Path, error) {
+	var steps []Path
+	switch {
+	case p.peek("[]byte(") || p.peek("[]rune("):
+		step, err := p.conversion()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	case p.accept("("):
+		group := p.peek("*") || p.peek("&")
+		inner, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		steps = append(steps, inner)
+		if !group {
+			steps = append(steps, Inter())
+		}
+	case p.peekSelector():
+		step, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+
+	for !p.done() {
+		switch {
+		case p.accept(".("):
+			name := p.ident()
+			t, ok := formattableKeyType[name]
+			if !ok {
+				return nil, p.errorf("unsupported type %q in type assertion", name)
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			steps = append(steps, AsType(t))
+		case p.accept("."):
+			if !p.peekSelector() {
+				return nil, p.errorf("expected field name")
+			}
+			step, err := p.selector()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		case p.accept("["):
+			step, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		case p.accept("{"):
+			key, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("}"); err != nil {
+				return nil, err
+			}
+			steps = append(steps, MapKeyPart(key))
+		default:
+			return Join(steps...), nil
+		}
+	}
+	return Join(steps...), nil
+}
+
+// conversion parses an index into a string converted to []byte or []rune.
+func (p *parser) conversion() (Path, error) {
+	isRune := p.accept("[]rune(")
+	if !isRune {
+		p.accept("[]byte(")
+	}
+	inner, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	if !p.peekDigit() {
+		return nil, p.errorf("expected index")
+	}
+	i, err := p.index()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	if isRune {
+		return Join(inner, RuneAt(i)), nil
+	}
+	return Join(inner, ByteAt(i)), nil
+}
+
+func (p *parser) peekSelector() bool {
+	return p.peek("~") || p.peek("#") || p.peekIdent()
+}
+
+// selector parses a field name, an unexported field name prefixed by '~', a field index
+// prefixed by '#', or a method name followed by "()".
+func (p *parser) selector() (Path, error) {
+	if p.accept("#") {
+		var index []int
+		for {
+			d := p.digits()
+			i, err := strconv.Atoi(d)
+			if err != nil {
+				return nil, p.errorf("invalid field index %q", d)
+			}
+			index = append(index, i)
+			if !p.accept(",") {
+				return FieldIndex(index), nil
+			}
+		}
+	}
+	if p.accept("~") {
+		if !p.peekIdent() {
+			return nil, p.errorf("expected field name")
+		}
+		return UnexportedField(p.ident()), nil
+	}
+	name := p.ident()
+	if p.accept("()") {
+		return Method(name), nil
+	}
+	if p.tagKey != "" {
+		return TaggedField(p.tagKey, name), nil
+	}
+	return ExportedField(name), nil
+}
+
+func (p *parser) peekIdent() bool {
+	r, _ := utf8.DecodeRuneInString(p.rest())
+	return isIdentRune(r, true)
+}
+
+func (p *parser) ident() string {
+	start := p.pos
+	for !p.done() {
+		r, size := utf8.DecodeRuneInString(p.rest())
+		if !isIdentRune(r, p.pos == start) {
+			break
+		}
+		p.pos += size
+	}
+	return p.in[start:p.pos]
+}
+
+func (p *parser) digits() string {
+	start := p.pos
+	for !p.done() && p.in[p.pos] >= '0' && p.in[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.in[start:p.pos]
+}
+
+// bracket parses the contents of [...], after the opening bracket.
+func (p *parser) bracket() (Path, error) {
+	var step Path
+	if p.accept("-") {
+		n, err := p.index()
+		if err != nil {
+			return nil, err
+		}
+		if n < 1 {
+			return nil, p.errorf("index %d from end is less than 1", n)
+		}
+		step = FromEnd(n)
+	} else if p.peekDigit() {
+		i, err := p.index()
+		if err != nil {
+			return nil, err
+		}
+		step = Index(i)
+		if p.accept(":") {
+			hi, err := p.index()
+			if err != nil {
+				return nil, err
+			}
+			if hi < i {
+				return nil, p.errorf("slice range %d:%d ends before it starts", i, hi)
+			}
+			step = SliceRange(i, hi)
+		}
+	} else {
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		step = MapValueOfKeyPart(key)
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return step, nil
+}
+
+func (p *parser) peekDigit() bool {
+	return !p.done() && p.in[p.pos] >= '0' && p.in[p.pos] <= '9'
+}
+
+func (p *parser) index() (int, error) {
+	d := p.digits()
+	i, err := strconv.Atoi(d)
+	if err != nil {
+		return 0, p.errorf("invalid index %q", d)
+	}
+	return i, nil
+}
+
+func (p *parser) key() (reflect.Value, error) {
+	if quoted, err := strconv.QuotedPrefix(p.rest()); err == nil {
+		p.pos += len(quoted)
+		s, err := strconv.Unquote(quoted)
+		if err != nil {
+			return zeroValue, p.errorf("invalid string %s", quoted)
+		}
+		return reflect.ValueOf(s), nil
+	}
+	if !p.peekIdent() {
+		return zeroValue, p.errorf("expected map key")
+	}
+	name := p.ident()
+	switch name {
+	case "true":
+		return reflect.ValueOf(true), nil
+	case "false":
+		return reflect.ValueOf(false), nil
+	}
+	t, ok := formattableKeyType[name]
+	if !ok || t.Kind() == reflect.Bool || t.Kind() == reflect.String {
+		return zeroValue, p.errorf("unsupported map key type %q", name)
+	}
+	if err := p.expect("("); err != nil {
+		return zeroValue, err
+	}
+	end := strings.IndexByte(p.rest(), ')')
+	if end < 0 {
+		return zeroValue, p.errorf("expected %q", ")")
+	}
+	lit := p.rest()[:end]
+	key := reflect.New(t).Elem()
+	var err error
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(lit, 10, t.Bits()); err == nil {
+			key.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		if u, err = strconv.ParseUint(lit, 10, t.Bits()); err == nil {
+			key.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(lit, t.Bits()); err == nil {
+			key.SetFloat(f)
+		}
+	}
+	if err != nil {
+		return zeroValue, p.errorf("invalid %s literal %q", name, lit)
+	}
+	p.pos += end + 1
+	return key, nil
+}
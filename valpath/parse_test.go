@@ -0,0 +1,312 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		want valpath.Path
+		// canonical is the output of Format, if it differs from in.
+		canonical string
+	}{
+		{
+			name: "empty",
+			in:   "",
+			want: valpath.Empty(),
+		},
+		{
+			name: "nested fields",
+			in:   "Inner.Int",
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.ExportedField("Int")),
+		},
+		{
+			name:      "leading dot",
+			in:        ".Inner",
+			want:      valpath.ExportedField("Inner"),
+			canonical: "Inner",
+		},
+		{
+			name: "index",
+			in:   "Items[3]",
+			want: valpath.Join(valpath.ExportedField("Items"), valpath.Index(3)),
+		},
+		{
+			name: "index from end",
+			in:   "Items[-1]",
+			want: valpath.Join(valpath.ExportedField("Items"), valpath.FromEnd(1)),
+		},
+		{
+			name: "slice range",
+			in:   "Items[1:3][0]",
+			want: valpath.Join(valpath.ExportedField("Items"), valpath.SliceRange(1, 3), valpath.Index(0)),
+		},
+		{
+			name: "byte of string",
+			in:   "[]byte(Inner.Name)[2]",
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.ExportedField("Name"), valpath.ByteAt(2)),
+		},
+		{
+			name: "rune of dereferenced string",
+			in:   "[]rune(*Name)[0]",
+			want: valpath.Join(valpath.ExportedField("Name"), valpath.Deref(), valpath.RuneAt(0)),
+		},
+		{
+			name: "rune at root",
+			in:   "[]rune()[1]",
+			want: valpath.RuneAt(1),
+		},
+		{
+			name: "type assertion",
+			in:   "Any.(string)",
+			want: valpath.Join(valpath.ExportedField("Any"), valpath.As[string]()),
+		},
+		{
+			name: "index at root",
+			in:   "[0][1]",
+			want: valpath.Join(valpath.Index(0), valpath.Index(1)),
+		},
+		{
+			name: "string map value",
+			in:   `Tags["env"]`,
+			want: valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey("env")),
+		},
+		{
+			name:      "raw string map value",
+			in:        "Tags[`a\"b`]",
+			want:      valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey(`a"b`)),
+			canonical: `Tags["a\"b"]`,
+		},
+		{
+			name: "typed map value",
+			in:   "M[int64(-7)]",
+			want: valpath.Join(valpath.ExportedField("M"), valpath.MapValueOfKey(int64(-7))),
+		},
+		{
+			name: "float map value",
+			in:   "M[float32(1.5)]",
+			want: valpath.Join(valpath.ExportedField("M"), valpath.MapValueOfKey(float32(1.5))),
+		},
+		{
+			name: "bool map key",
+			in:   "M{true}",
+			want: valpath.Join(valpath.ExportedField("M"), valpath.MapKey(true)),
+		},
+		{
+			name: "deref",
+			in:   "*Ptr",
+			want: valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref()),
+		},
+		{
+			name: "deref applies to whole selector",
+			in:   "*Ptr.Int",
+			want: valpath.Join(valpath.ExportedField("Ptr"), valpath.ExportedField("Int"), valpath.Deref()),
+		},
+		{
+			name: "parenthesized deref",
+			in:   "(*Ptr).Int",
+			want: valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name: "addr",
+			in:   "&Inner",
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.Addr()),
+		},
+		{
+			name: "addr then deref",
+			in:   "(*&Inner).Int",
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.Addr(), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name: "root deref",
+			in:   "(**).Int",
+			want: valpath.Join(valpath.Deref(), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name: "interface",
+			in:   "(IFace).Name",
+			want: valpath.Join(valpath.ExportedField("IFace"), valpath.Inter(), valpath.ExportedField("Name")),
+		},
+		{
+			name: "dereferenced interface",
+			in:   "((*Ptr)).Name",
+			want: valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.Inter(), valpath.ExportedField("Name")),
+		},
+		{
+			name: "method",
+			in:   "IFace.String()",
+			want: valpath.Join(valpath.ExportedField("IFace"), valpath.Method("String")),
+		},
+		{
+			name: "method through deref",
+			in:   "(*Ptr).Get().Int",
+			want: valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.Method("Get"), valpath.ExportedField("Int")),
+		},
+		{
+			name: "unexported field",
+			in:   "Inner.~count",
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.UnexportedField("count")),
+		},
+		{
+			name: "root unexported field",
+			in:   "~count",
+			want: valpath.UnexportedField("count"),
+		},
+		{
+			name: "field index",
+			in:   "Outer.#0,0",
+			want: valpath.Join(valpath.ExportedField("Outer"), valpath.FieldIndex([]int{0, 0})),
+		},
+		{
+			name: "root interface",
+			in:   "()[0]",
+			want: valpath.Join(valpath.Inter(), valpath.Index(0)),
+		},
+		{
+			name: "everything",
+			in:   `*(*(A[1]["k"])).B{uint8(2)}`,
+			want: valpath.Join(
+				valpath.ExportedField("A"), valpath.Index(1), valpath.MapValueOfKey("k"), valpath.Inter(),
+				valpath.Deref(), valpath.ExportedField("B"), valpath.MapKey(uint8(2)), valpath.Deref()),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valpath.Parse(tt.in)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !valpath.Equal(got, tt.want) {
+				t.Errorf("got path %s, want %s", got, tt.want)
+			}
+			gotText, err := valpath.Format(got)
+			if err != nil {
+				t.Fatalf("got format error %v, want no error", err)
+			}
+			canonical := tt.canonical
+			if canonical == "" {
+				canonical = tt.in
+			}
+			if gotText != canonical {
+				t.Errorf("got formatted %q, want %q", gotText, canonical)
+			}
+		})
+	}
+}
+
+func TestParseTraverse(t *testing.T) {
+	type Inner struct {
+		Int int
+	}
+	type Root struct {
+		Ptr   *Inner
+		Items []Inner
+		Tags  map[string]int
+		M     map[int64]string
+		IFace any
+	}
+	in := reflect.ValueOf(Root{
+		Ptr:   &Inner{Int: 1},
+		Items: []Inner{{Int: 2}, {Int: 3}},
+		Tags:  map[string]int{"env": 4},
+		M:     map[int64]string{7: "seven"},
+		IFace: Inner{Int: 5},
+	})
+	testCases := []struct {
+		in      string
+		wantAny any
+	}{
+		{in: "(*Ptr).Int", wantAny: 1},
+		{in: "Items[1].Int", wantAny: 3},
+		{in: `Tags["env"]`, wantAny: 4},
+		{in: "M[int64(7)]", wantAny: "seven"},
+		{in: "(IFace).Int", wantAny: 5},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.in, func(t *testing.T) {
+			p, err := valpath.Parse(tt.in)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			got, err := p.Traverse(in)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.wantAny) {
+				t.Errorf("got value %v, want %v", got.Interface(), tt.wantAny)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		"Inner.",
+		"Items[",
+		"Items[3",
+		"Items[-0]",
+		"Any.(Foo)",
+		"Any.(int",
+		"Any.(?)",
+		"[]rune(Name)",
+		"[]rune(Name)[x]",
+		"[]byte(Name[0]",
+		"Items[-]",
+		"Items[1:]",
+		"Items[:2]",
+		"Items[10:0]",
+		`Tags["env]`,
+		"M[int64(x)]",
+		"M[int8(300)]",
+		"M[complex128(1)]",
+		"M{3}",
+		"(*Ptr",
+		"Inner Int",
+		"Inner..Int",
+		"Inner.~",
+		"Inner.~~count",
+		"Inner.#",
+		"Inner.#0,",
+	} {
+		t.Run(in, func(t *testing.T) {
+			got, err := valpath.Parse(in)
+			if !errors.Is(err, valpath.ErrSyntax) {
+				t.Errorf("got error %v, want %v", err, valpath.ErrSyntax)
+			}
+			if got != nil {
+				t.Errorf("got path %v, want nil", got)
+			}
+		})
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	type named string
+	for _, p := range []valpath.Path{
+		valpath.Index(-1),
+		valpath.FromEnd(0),
+		valpath.As[testtypes.Inner](),
+		valpath.RuneAt(-1),
+		valpath.SliceRange(2, 1),
+		valpath.ExportedField("not an identifier"),
+		valpath.MapValueOfKey(named("key")),
+		valpath.MapKey(struct{ A int }{A: 1}),
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			got, err := valpath.Format(p)
+			if !errors.Is(err, valpath.ErrNotFormattable) {
+				t.Errorf("got error %v, want %v", err, valpath.ErrNotFormattable)
+			}
+			if got != "" {
+				t.Errorf("got %q, want empty string", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package valpath
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// PointerTo returns a pointer to the location addressed by p within root.  If the location
+// isn't addressable, the error names the step that produced an unaddressable value and
+// explains why, or explains that root itself isn't addressable.
+func PointerTo(root reflect.Value, p Path) (reflect.Value, error) {
+	steps := slices.Collect(p.elems())
+	v := root
+	// cause is the position of the latest step that produced an unaddressable value, either
+	// from an addressable one or because its values are never addressable, or -1 if no step
+	// explains why the value is unaddressable.
+	cause := -1
+	var hidden readOnlyTracker
+	for pos, step := range steps {
+		next, err := step.Traverse(v)
+		if err != nil {
+			return zeroValue, atPos(err, step, typeOf(v), steps[:pos], pos)
+		}
+		next = hidden.next(step, next)
+		_, never := unaddressableReason(step)
+		if _, ok := step.(AsTypePart); ok && v.Kind() != reflect.Interface {
+			// Asserting the type of a concrete value passes it through unchanged.
+			never = false
+		}
+		switch {
+		case next.CanAddr():
+			cause = -1
+		case v.CanAddr() || never:
+			cause = pos
+		}
+		v = next
+	}
+	if !v.IsValid() {
+		return zeroValue, newPathError(Empty(), v, ErrInvalidValue)
+	}
+	if v.CanAddr() {
+		return v.Addr(), nil
+	}
+	if cause < 0 {
+		err := fmt.Errorf("%w: the root value is not addressable; traverse from a pointer with a leading Deref step", ErrNotAddressable)
+		return zeroValue, newPathError(Empty(), root, err)
+	}
+	step := steps[cause]
+	input, _ := Join(steps[:cause]...).Traverse(root)
+	reason, ok := unaddressableReason(step)
+	if !ok {
+		reason = fmt.Sprintf("%s produces a value that is not addressable", step)
+	}
+	err := fmt.Errorf("%w: %s", ErrNotAddressable, reason)
+	return zeroValue, atPos(err, step, typeOf(input), steps[:cause], cause)
+}
+
+// unaddressableReason explains why the values that step produces are never addressable, if
+// that is the case.
+func unaddressableReason(step Path) (string, bool) {
+	switch step.(type) {
+	case MapValueOfKeyPart:
+		return "map values are not addressable", true
+	case MapKeyPart:
+		return "map keys are not addressable", true
+	case InterPart, AsTypePart:
+		return "values inside interfaces are not addressable", true
+	case MethodPart:
+		return "method results are not addressable", true
+	case UnexportedFieldPart:
+		return "unexported fields are read through a copy", true
+	case ByteAtPart, RuneAtPart:
+		return "bytes and runes of strings are not addressable", true
+	case SliceRangePart:
+		return "slice expressions produce values that are not addressable", true
+	case AddrPart:
+		return "pointers produced by Addr are not addressable", true
+	default:
+		return "", false
+	}
+}
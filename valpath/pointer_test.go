@@ -0,0 +1,173 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+func TestPointerTo(t *testing.T) {
+	root := newSetRoot()
+	ptrRoot := reflect.ValueOf(root)
+
+	t.Run("addressable", func(t *testing.T) {
+		testCases := []struct {
+			name string
+			path valpath.Path
+			want any
+		}{
+			{
+				name: "field",
+				path: valpath.Join(valpath.Deref(), valpath.ExportedField("Int")),
+				want: &root.Int,
+			},
+			{
+				name: "through pointer",
+				path: valpath.Join(valpath.Deref(), valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Int")),
+				want: &root.Ptr.Int,
+			},
+			{
+				name: "slice element",
+				path: valpath.Join(valpath.Deref(), valpath.ExportedField("Slice"), valpath.Index(1)),
+				want: &root.Slice[1],
+			},
+			{
+				name: "through pointer in map",
+				path: valpath.Join(valpath.Deref(), valpath.ExportedField("PtrMap"), valpath.MapValueOfKey("a"), valpath.Deref()),
+				want: root.PtrMap["a"],
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				got, err := valpath.PointerTo(ptrRoot, tc.path)
+				if err != nil {
+					t.Fatalf("got error %v, want no error", err)
+				}
+				if got.Interface() != tc.want {
+					t.Errorf("got %v, want %v", got.Interface(), tc.want)
+				}
+			})
+		}
+	})
+
+	t.Run("not addressable", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			root    reflect.Value
+			path    valpath.Path
+			wantPos int
+			wantMsg string
+		}{
+			{
+				name:    "non-pointer root",
+				root:    reflect.ValueOf(*root),
+				path:    valpath.Join(valpath.ExportedField("Inner"), valpath.ExportedField("Int")),
+				wantPos: 0,
+				wantMsg: "root value is not addressable",
+			},
+			{
+				name:    "map value from non-pointer root",
+				root:    reflect.ValueOf(map[string]int{"a": 1}),
+				path:    valpath.MapValueOfKey("a"),
+				wantPos: 0,
+				wantMsg: "map values are not addressable",
+			},
+			{
+				name:    "map value then field from non-pointer root",
+				root:    reflect.ValueOf(*root),
+				path:    valpath.Join(valpath.ExportedField("Map"), valpath.MapValueOfKey("a"), valpath.ExportedField("Int")),
+				wantPos: 1,
+				wantMsg: "map values are not addressable",
+			},
+			{
+				name:    "map value",
+				root:    ptrRoot,
+				path:    valpath.Join(valpath.Deref(), valpath.ExportedField("Map"), valpath.MapValueOfKey("a"), valpath.ExportedField("Int")),
+				wantPos: 2,
+				wantMsg: "map values are not addressable",
+			},
+			{
+				name:    "interface content",
+				root:    ptrRoot,
+				path:    valpath.Join(valpath.Deref(), valpath.ExportedField("IFace"), valpath.Inter()),
+				wantPos: 2,
+				wantMsg: "values inside interfaces are not addressable",
+			},
+			{
+				name:    "method result",
+				root:    ptrRoot,
+				path:    valpath.Join(valpath.Deref(), valpath.ExportedField("IFace"), valpath.Method("String")),
+				wantPos: 2,
+				wantMsg: "method results are not addressable",
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := valpath.PointerTo(tc.root, tc.path)
+				if !errors.Is(err, valpath.ErrNotAddressable) {
+					t.Fatalf("got error %v, want %v", err, valpath.ErrNotAddressable)
+				}
+				var pathErr *valpath.PathError
+				if !errors.As(err, &pathErr) || pathErr.Pos != tc.wantPos {
+					t.Errorf("got error %v, want error at step %d", err, tc.wantPos)
+				}
+				if !strings.Contains(err.Error(), tc.wantMsg) {
+					t.Errorf("got error %q, want it to mention %q", err, tc.wantMsg)
+				}
+			})
+		}
+	})
+
+	t.Run("traverse error", func(t *testing.T) {
+		_, err := valpath.PointerTo(ptrRoot, valpath.Join(valpath.Deref(), valpath.ExportedField("Missing")))
+		if !errors.Is(err, valpath.ErrNoSuchField) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNoSuchField)
+		}
+	})
+}
+
+func TestAddr(t *testing.T) {
+	root := newSetRoot()
+
+	t.Run("pointer method through addressed field", func(t *testing.T) {
+		p := valpath.Join(valpath.Deref(), valpath.ExportedField("Inner"), valpath.Addr())
+		got, err := p.Traverse(reflect.ValueOf(root))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got.Interface() != &root.Inner {
+			t.Errorf("got %v, want %v", got.Interface(), &root.Inner)
+		}
+	})
+
+	t.Run("set through addr and deref", func(t *testing.T) {
+		p := valpath.Join(valpath.ExportedField("Inner"), valpath.Addr(), valpath.Deref(), valpath.ExportedField("Int"))
+		if err := valpath.Set(reflect.ValueOf(root).Elem(), p, reflect.ValueOf(5)); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if root.Inner.Int != 5 {
+			t.Errorf("got %d, want 5", root.Inner.Int)
+		}
+	})
+
+	t.Run("not addressable", func(t *testing.T) {
+		_, err := valpath.Addr().Traverse(reflect.ValueOf(testtypes.Inner{}))
+		if !errors.Is(err, valpath.ErrNotAddressable) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNotAddressable)
+		}
+	})
+
+	t.Run("type", func(t *testing.T) {
+		got, err := valpath.ResolveType(reflect.TypeFor[setRoot](), valpath.Join(valpath.ExportedField("Inner"), valpath.Addr()))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if want := reflect.TypeFor[*testtypes.Inner](); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package valpath
+
+import (
+	"errors"
+	"reflect"
+)
+
+type dynamicType struct{}
+
+// Dynamic is returned by ResolveType in place of a type that depends on the dynamic type of
+// an interface value, and so can't be known without a value.
+var Dynamic = reflect.TypeFor[dynamicType]()
+
+// ResolveType returns the type of the value that p addresses within values of type t,
+// checking each step the same way Traverse would without needing a value.  Checks that depend
+// on the value, such as whether a pointer is nil or a map key is present, are skipped.  Once an
+// interface is unwrapped with Inter, the remaining steps can't be checked and Dynamic is
+// returned.
+func ResolveType(t reflect.Type, p Path) (reflect.Type, error) {
+	got, err := p.resolveType(t)
+	if errors.Is(err, errDynamic) {
+		return Dynamic, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return got, nil
+}
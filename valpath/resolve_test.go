@@ -0,0 +1,157 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+func TestResolveType(t *testing.T) {
+	type Root struct {
+		Outer  testtypes.OuterPtr
+		Items  []testtypes.Inner
+		Fixed  [2]int
+		Tags   map[string]*testtypes.Inner
+		IFace  testtypes.IFace
+		hidden int
+	}
+	root := reflect.TypeFor[Root]()
+	testCases := []struct {
+		name    string
+		path    valpath.Path
+		want    reflect.Type
+		wantErr error
+		wantPos int
+	}{
+		{
+			name: "empty",
+			path: valpath.Empty(),
+			want: root,
+		},
+		{
+			name: "promoted field",
+			path: valpath.Join(valpath.ExportedField("Outer"), valpath.ExportedField("Int")),
+			want: reflect.TypeFor[int](),
+		},
+		{
+			name: "explicit embedded pointer",
+			path: valpath.Join(valpath.ExportedField("Outer"), valpath.ExportedField("Inner"), valpath.Deref()),
+			want: reflect.TypeFor[testtypes.Inner](),
+		},
+		{
+			name: "slice element",
+			path: valpath.Join(valpath.ExportedField("Items"), valpath.Index(100), valpath.ExportedField("Int")),
+			want: reflect.TypeFor[int](),
+		},
+		{
+			name: "map value",
+			path: valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey("missing"), valpath.Deref()),
+			want: reflect.TypeFor[testtypes.Inner](),
+		},
+		{
+			name: "map key",
+			path: valpath.Join(valpath.ExportedField("Tags"), valpath.MapKey("k")),
+			want: reflect.TypeFor[string](),
+		},
+		{
+			name: "method on interface",
+			path: valpath.Join(valpath.ExportedField("IFace"), valpath.Method("String")),
+			want: reflect.TypeFor[string](),
+		},
+		{
+			name: "field index",
+			path: valpath.FieldIndex([]int{0, 0, 0}),
+			want: reflect.TypeFor[int](),
+		},
+		{
+			name: "unexported field",
+			path: valpath.UnexportedField("hidden"),
+			want: reflect.TypeFor[int](),
+		},
+		{
+			name: "interface is dynamic",
+			path: valpath.Join(valpath.ExportedField("IFace"), valpath.Inter(), valpath.ExportedField("Anything")),
+			want: valpath.Dynamic,
+		},
+		{
+			name:    "missing field",
+			path:    valpath.Join(valpath.ExportedField("Outer"), valpath.ExportedField("Missing")),
+			wantErr: valpath.ErrNoSuchField,
+			wantPos: 1,
+		},
+		{
+			name:    "array index out of range",
+			path:    valpath.Join(valpath.ExportedField("Fixed"), valpath.Index(2)),
+			wantErr: valpath.ErrIndexOutOfRange,
+			wantPos: 1,
+		},
+		{
+			name:    "wrong key type",
+			path:    valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey(1)),
+			wantErr: valpath.ErrKindMismatch,
+			wantPos: 1,
+		},
+		{
+			name:    "deref of non-pointer",
+			path:    valpath.Join(valpath.ExportedField("Items"), valpath.Deref()),
+			wantErr: valpath.ErrKindMismatch,
+			wantPos: 1,
+		},
+		{
+			name:    "inter of non-interface",
+			path:    valpath.Join(valpath.ExportedField("Items"), valpath.Inter()),
+			wantErr: valpath.ErrKindMismatch,
+			wantPos: 1,
+		},
+		{
+			name:    "missing method",
+			path:    valpath.Join(valpath.ExportedField("IFace"), valpath.Method("Missing")),
+			wantErr: valpath.ErrNoSuchMethod,
+			wantPos: 1,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valpath.ResolveType(root, tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				var pathErr *valpath.PathError
+				if !errors.As(err, &pathErr) || pathErr.Pos != tt.wantPos {
+					t.Errorf("got error %v, want error at step %d", err, tt.wantPos)
+				}
+				if got != nil {
+					t.Errorf("got type %v, want nil", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if got != tt.want {
+				t.Errorf("got type %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("matches traverse", func(t *testing.T) {
+		v := reflect.ValueOf(getters{n: 1})
+		for _, p := range []valpath.Path{valpath.Method("Value"), valpath.Method("Checked"), valpath.Method("Ptr")} {
+			want, err := p.Traverse(v)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			got, err := valpath.ResolveType(v.Type(), p)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if got != want.Type() {
+				t.Errorf("got type %v, want %v", got, want.Type())
+			}
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package valpath
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+var ErrNotSelected = errors.New("selector did not return a pointer into its argument")
+
+// Of derives the path to the location that sel selects, so that
+//
+//	Of(func(t *T) any { return &t.Inner.Int })
+//
+// is the path Inner.Int, checked by the compiler and kept up to date when fields are renamed.
+// sel is called once with a zero T whose nil pointers have been allocated, and the pointer it
+// returns is located by comparing addresses, descending into struct fields, array elements
+// and pointers.  Recursive types are allocated maxRecursion levels deep, so selectors may
+// follow a linked list or tree that far.  Locations within slices, maps and interfaces can't
+// be selected.
+func Of[T any](sel func(t *T) any) (Path, error) {
+	root := reflect.New(reflect.TypeFor[T]())
+	allocate(root.Elem(), map[reflect.Type]int{})
+	got, err := callSelector(sel, root.Interface().(*T))
+	if err != nil {
+		return nil, err
+	}
+	target := reflect.ValueOf(got)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return nil, fmt.Errorf("valpath: selector returned %v: %w", typeOf(target), ErrNotSelected)
+	}
+	steps, ok := locate(root.Elem(), target.Pointer(), target.Type().Elem())
+	if !ok {
+		return nil, fmt.Errorf("valpath: selector returned %v: %w", target.Type(), ErrNotSelected)
+	}
+	return Join(steps...), nil
+}
+
+// MustOf is like Of, but panics if the path can't be derived.
+func MustOf[T any](sel func(t *T) any) Path {
+	p, err := Of(sel)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func callSelector[T any](sel func(t *T) any, t *T) (got any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("valpath: selector panicked: %v: %w", r, ErrNotSelected)
+		}
+	}()
+	return sel(t), nil
+}
+
+// maxRecursion is how many times Of allocates a type within itself.  Each level multiplies
+// the allocations by the number of recursive pointers in the type, so trees stay small.
+const maxRecursion = 4
+
+// allocate fills nil pointers within v with pointers to new zero values, so that selectors
+// can follow them.  active counts the values of each type that are being allocated, and
+// pointers to types already maxRecursion deep are left nil, so that recursive types end.
+func allocate(v reflect.Value, active map[reflect.Type]int) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := v.Type().Elem()
+		if !v.IsNil() || active[elem] >= maxRecursion {
+			return
+		}
+		// v may be an unexported field, which reflect won't set.  Its memory belongs to the
+		// value that Of allocated, so it's safe to write to directly.
+		v = reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+		v.Set(reflect.New(elem))
+		active[elem]++
+		allocate(v.Elem(), active)
+		active[elem]--
+	case reflect.Struct:
+		for i := range v.NumField() {
+			allocate(v.Field(i), active)
+		}
+	case reflect.Array:
+		for i := range v.Len() {
+			allocate(v.Index(i), active)
+		}
+	}
+}
+
+// locate returns the steps from the addressable value v to the value of type t at addr.
+func locate(v reflect.Value, addr uintptr, t reflect.Type) ([]Path, bool) {
+	base := v.UnsafeAddr()
+	if base == addr && v.Type() == t {
+		return nil, true
+	}
+	// Values outside of v's own memory may still be reached through pointers within it.
+	within := addr >= base && addr < base+v.Type().Size()
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if rest, ok := locate(v.Field(i), addr, t); ok {
+				return append(explicitField(v.Type(), []int{i}, true), rest...), true
+			}
+		}
+	case reflect.Array:
+		first, end := 0, v.Len()
+		if size := v.Type().Elem().Size(); within && size > 0 {
+			first = int((addr - base) / size)
+			end = first + 1
+		}
+		for i := first; i < end; i++ {
+			if rest, ok := locate(v.Index(i), addr, t); ok {
+				return append([]Path{Index(i)}, rest...), true
+			}
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, false
+		}
+		if rest, ok := locate(v.Elem(), addr, t); ok {
+			return append([]Path{Deref()}, rest...), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,134 @@
+package valpath_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+type selectorNode struct {
+	Value int
+	Arr   [3]int
+	Next  *selectorNode
+}
+
+type selectorRoot struct {
+	Int   int
+	Inner testtypes.Inner
+	Ptr   *testtypes.Inner
+	Array [3]testtypes.Inner
+	testtypes.Outer
+	*selectorNode
+	Slice []int
+	Any   any
+	count int
+}
+
+func TestOf(t *testing.T) {
+	testCases := []struct {
+		name    string
+		sel     func(r *selectorRoot) any
+		want    valpath.Path
+		wantErr error
+	}{
+		{
+			name: "root",
+			sel:  func(r *selectorRoot) any { return r },
+			want: valpath.Empty(),
+		},
+		{
+			name: "field",
+			sel:  func(r *selectorRoot) any { return &r.Int },
+			want: valpath.ExportedField("Int"),
+		},
+		{
+			name: "nested field",
+			sel:  func(r *selectorRoot) any { return &r.Inner.Int },
+			want: valpath.Join(valpath.ExportedField("Inner"), valpath.ExportedField("Int")),
+		},
+		{
+			name: "struct containing first field",
+			sel:  func(r *selectorRoot) any { return &r.Inner },
+			want: valpath.ExportedField("Inner"),
+		},
+		{
+			name: "through pointer",
+			sel:  func(r *selectorRoot) any { return &r.Ptr.Int },
+			want: valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name: "pointer itself",
+			sel:  func(r *selectorRoot) any { return &r.Ptr },
+			want: valpath.ExportedField("Ptr"),
+		},
+		{
+			name: "array element",
+			sel:  func(r *selectorRoot) any { return &r.Array[2].Int },
+			want: valpath.Join(valpath.ExportedField("Array"), valpath.Index(2), valpath.ExportedField("Int")),
+		},
+		{
+			name: "promoted through embedded struct",
+			sel:  func(r *selectorRoot) any { return &r.Outer.Int },
+			want: valpath.Join(valpath.ExportedField("Outer"), valpath.ExportedField("Inner"), valpath.ExportedField("Int")),
+		},
+		{
+			name: "through embedded pointer",
+			sel:  func(r *selectorRoot) any { return &r.Value },
+			want: valpath.Join(valpath.UnexportedField("selectorNode"), valpath.Deref(), valpath.ExportedField("Value")),
+		},
+		{
+			name: "unexported field",
+			sel:  func(r *selectorRoot) any { return &r.count },
+			want: valpath.UnexportedField("count"),
+		},
+		{
+			name: "recursive type",
+			sel:  func(r *selectorRoot) any { return &r.Next.Next.Arr[2] },
+			want: valpath.Join(
+				valpath.UnexportedField("selectorNode"), valpath.Deref(),
+				valpath.ExportedField("Next"), valpath.Deref(),
+				valpath.ExportedField("Next"), valpath.Deref(),
+				valpath.ExportedField("Arr"), valpath.Index(2),
+			),
+		},
+		{
+			name:    "recursive type ends",
+			sel:     func(r *selectorRoot) any { return &r.Next.Next.Next.Next.Value },
+			wantErr: valpath.ErrNotSelected,
+		},
+		{
+			name:    "slice element",
+			sel:     func(r *selectorRoot) any { return &r.Slice[0] },
+			wantErr: valpath.ErrNotSelected,
+		},
+		{
+			name:    "not a pointer",
+			sel:     func(r *selectorRoot) any { return r.Int },
+			wantErr: valpath.ErrNotSelected,
+		},
+		{
+			name:    "pointer outside the root",
+			sel:     func(r *selectorRoot) any { return new(int) },
+			wantErr: valpath.ErrNotSelected,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valpath.Of(tt.sel)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !valpath.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package valpath
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	ErrNotAddressable = errors.New("value is not addressable")
+	ErrNotAssignable  = errors.New("value is not assignable")
+)
+
+// AssignError reports that a value of type From cannot be assigned or converted to the type
+// To of the location being set.  It matches ErrNotAssignable with errors.Is.
+type AssignError struct {
+	From reflect.Type
+	To   reflect.Type
+}
+
+func (e *AssignError) Error() string {
+	return fmt.Sprintf("cannot assign %s to %s", e.From, e.To)
+}
+
+func (e *AssignError) Is(target error) bool {
+	return target == ErrNotAssignable
+}
+
+// slot is a location produced by a single step, in a form that can be modified.
+type slot struct {
+	// v is the addressed child.
+	v reflect.Value
+	// store writes v back into its parent after v has been modified.  It is nil if v is a
+	// view into its parent rather than a copy.
+	store func() error
+	// indirect is true if v lives outside of its parent's own memory (behind a pointer,
+	// slice or map), so that modifying v leaves the parent itself unchanged.
+	indirect bool
+	// vivified is true if producing v required modifying the parent itself, for example by
+	// allocating a pointer that was nil.
+	vivified bool
+}
+
+// SetOptions controls the behavior of SetWith.
+type SetOptions struct {
+	// Vivify allocates nil pointers, makes nil maps, grows slices that are too short for an
+	// Index step, and fills nil interfaces using Factories, instead of failing.
+	Vivify bool
+	// Factories produces values to fill nil interfaces of the given interface types when
+	// Vivify is set.
+	Factories map[reflect.Type]func() reflect.Value
+	// AllowUnexported permits modifying values reached through UnexportedField steps.
+	AllowUnexported bool
+
+	// copyOnWrite replaces each pointer, slice and map along the path with a copy before
+	// stepping into it, so that values shared with other roots are left unchanged.
+	copyOnWrite bool
+}
+
+// Set stores newVal at the location addressed by p within root.  Intermediate values that
+// are not addressable, such as map entries and interface contents, are copied, modified, and
+// stored back.  If newVal is the zero reflect.Value, the location is set to its zero value.
+// newVal is converted to the type of the location if it is not directly assignable.
+func Set(root reflect.Value, p Path, newVal reflect.Value) error {
+	return SetWith(root, p, newVal, SetOptions{})
+}
+
+// SetWith is like Set, but with behavior controlled by opts.
+func SetWith(root reflect.Value, p Path, newVal reflect.Value, opts SetOptions) error {
+	s, err := p.edit(root, &opts)
+	if err != nil {
+		return err
+	}
+	if err := assign(s.v, newVal); err != nil {
+		return leafError(p, typeOf(s.v), err)
+	}
+	if s.store != nil {
+		return s.store()
+	}
+	return nil
+}
+
+// SetAt stores v at the location addressed by p within *ptr.
+func SetAt[T any](ptr *T, p Path, v any) error {
+	return Set(reflect.ValueOf(ptr).Elem(), p, reflect.ValueOf(v))
+}
+
+func assign(dst, src reflect.Value) error {
+	if !dst.CanSet() {
+		return ErrNotAddressable
+	}
+	t := dst.Type()
+	switch {
+	case !src.IsValid():
+		dst.SetZero()
+	case src.Type().AssignableTo(t):
+		dst.Set(src)
+	case convertible(src.Type(), t):
+		dst.Set(src.Convert(t))
+	default:
+		return &AssignError{From: src.Type(), To: t}
+	}
+	return nil
+}
+
+// convertible reports whether values of type from may be converted to type to, excluding
+// conversions from integers to strings, which yield a rune rather than a decimal string, and
+// conversions from slices to arrays, which panic if the slice is too short.
+func convertible(from, to reflect.Type) bool {
+	if from.Kind() == reflect.Slice && (to.Kind() == reflect.Array || to.Kind() == reflect.Pointer) {
+		return false
+	}
+	if to.Kind() == reflect.String {
+		switch from.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return false
+		}
+	}
+	return from.ConvertibleTo(to)
+}
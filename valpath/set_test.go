@@ -0,0 +1,328 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+type setRoot struct {
+	Int     int
+	Inner   testtypes.Inner
+	Ptr     *testtypes.Inner
+	Slice   []int
+	Array   [2]testtypes.Inner
+	Map     map[string]testtypes.Inner
+	PtrMap  map[string]*testtypes.Inner
+	NilMap  map[string]int
+	IFace   testtypes.IFace
+	Any     any
+	Nested  map[string]map[string]int
+	Counter int64
+	Name    string
+}
+
+func newSetRoot() *setRoot {
+	return &setRoot{
+		Ptr:    &testtypes.Inner{Int: 1},
+		Slice:  []int{1, 2, 3},
+		Map:    map[string]testtypes.Inner{"a": {Int: 1}},
+		PtrMap: map[string]*testtypes.Inner{"a": {Int: 1}},
+		IFace:  testtypes.IFaceImpl(1),
+		Any:    &testtypes.Inner{Int: 1},
+		Nested: map[string]map[string]int{"a": {"b": 1}},
+	}
+}
+
+func TestSet(t *testing.T) {
+	testCases := []struct {
+		name    string
+		path    valpath.Path
+		newVal  any
+		want    func(*setRoot)
+		wantErr error
+	}{
+		{
+			name:   "top-level field",
+			path:   valpath.ExportedField("Int"),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Int = 42 },
+		},
+		{
+			name:   "nested struct field",
+			path:   valpath.Join(valpath.ExportedField("Inner"), valpath.ExportedField("Int")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Inner.Int = 42 },
+		},
+		{
+			name:   "field through pointer",
+			path:   valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Int")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Ptr.Int = 42 },
+		},
+		{
+			name:   "slice element",
+			path:   valpath.Join(valpath.ExportedField("Slice"), valpath.Index(1)),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Slice[1] = 42 },
+		},
+		{
+			name:   "array element field",
+			path:   valpath.Join(valpath.ExportedField("Array"), valpath.Index(1), valpath.ExportedField("Int")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Array[1].Int = 42 },
+		},
+		{
+			name:   "struct-valued map entry modified deep inside",
+			path:   valpath.Join(valpath.ExportedField("Map"), valpath.MapValueOfKey("a"), valpath.ExportedField("Int")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Map["a"] = testtypes.Inner{Int: 42} },
+		},
+		{
+			name:   "new map entry",
+			path:   valpath.Join(valpath.ExportedField("Map"), valpath.MapValueOfKey("b")),
+			newVal: testtypes.Inner{Int: 42},
+			want:   func(r *setRoot) { r.Map["b"] = testtypes.Inner{Int: 42} },
+		},
+		{
+			name:   "pointer-valued map entry",
+			path:   valpath.Join(valpath.ExportedField("PtrMap"), valpath.MapValueOfKey("a"), valpath.Deref(), valpath.ExportedField("Int")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.PtrMap["a"].Int = 42 },
+		},
+		{
+			name:   "nested map",
+			path:   valpath.Join(valpath.ExportedField("Nested"), valpath.MapValueOfKey("a"), valpath.MapValueOfKey("c")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Nested["a"]["c"] = 42 },
+		},
+		{
+			name:   "interface field",
+			path:   valpath.ExportedField("IFace"),
+			newVal: testtypes.IFaceImpl(42),
+			want:   func(r *setRoot) { r.IFace = testtypes.IFaceImpl(42) },
+		},
+		{
+			name:   "interface contents",
+			path:   valpath.Join(valpath.ExportedField("IFace"), valpath.Inter()),
+			newVal: 42,
+			want:   func(r *setRoot) { r.IFace = testtypes.IFaceImpl(42) },
+		},
+		{
+			name:   "through interface holding pointer",
+			path:   valpath.Join(valpath.ExportedField("Any"), valpath.Inter(), valpath.Deref(), valpath.ExportedField("Int")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Any.(*testtypes.Inner).Int = 42 },
+		},
+		{
+			name:   "convertible value",
+			path:   valpath.ExportedField("Counter"),
+			newVal: int8(42),
+			want:   func(r *setRoot) { r.Counter = 42 },
+		},
+		{
+			name:   "zero value",
+			path:   valpath.ExportedField("Ptr"),
+			newVal: nil,
+			want:   func(r *setRoot) { r.Ptr = nil },
+		},
+		{
+			name:    "not assignable",
+			path:    valpath.ExportedField("Int"),
+			newVal:  "forty-two",
+			wantErr: valpath.ErrNotAssignable,
+		},
+		{
+			name:    "int to string is not a conversion",
+			path:    valpath.ExportedField("Name"),
+			newVal:  42,
+			wantErr: valpath.ErrNotAssignable,
+		},
+		{
+			name:    "nil map",
+			path:    valpath.Join(valpath.ExportedField("NilMap"), valpath.MapValueOfKey("a")),
+			newVal:  42,
+			wantErr: valpath.ErrNilPointer,
+		},
+		{
+			name:    "map key",
+			path:    valpath.Join(valpath.ExportedField("Map"), valpath.MapKey("a")),
+			newVal:  "b",
+			wantErr: valpath.ErrNotAddressable,
+		},
+		{
+			name:    "slice index out of range",
+			path:    valpath.Join(valpath.ExportedField("Slice"), valpath.Index(3)),
+			newVal:  42,
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.want == nil) == (tt.wantErr == nil) {
+				t.Fatal("exactly one of want and wantErr must be set")
+			}
+			got := newSetRoot()
+			err := valpath.SetAt(got, tt.path, tt.newVal)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			want := newSetRoot()
+			tt.want(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetNotAddressable(t *testing.T) {
+	in := testtypes.Inner{Int: 1}
+	err := valpath.Set(reflect.ValueOf(in), valpath.ExportedField("Int"), reflect.ValueOf(42))
+	if !errors.Is(err, valpath.ErrNotAddressable) {
+		t.Errorf("got error %v, want %v", err, valpath.ErrNotAddressable)
+	}
+
+	// Values reached through a pointer are addressable even from a non-addressable root.
+	ptr := &testtypes.Inner{Int: 1}
+	outer := testtypes.OuterPtr{Inner: ptr}
+	if err := valpath.Set(reflect.ValueOf(outer), valpath.ExportedField("Int"), reflect.ValueOf(42)); err != nil {
+		t.Fatalf("got error %v, want no error", err)
+	}
+	if ptr.Int != 42 {
+		t.Errorf("got %d, want 42", ptr.Int)
+	}
+}
+
+func TestSetAssignError(t *testing.T) {
+	r := newSetRoot()
+	err := valpath.SetAt(r, valpath.Join(valpath.ExportedField("Inner"), valpath.ExportedField("Int")), "x")
+	var assignErr *valpath.AssignError
+	if !errors.As(err, &assignErr) {
+		t.Fatalf("got error %v, want *valpath.AssignError", err)
+	}
+	if assignErr.From != reflect.TypeFor[string]() || assignErr.To != reflect.TypeFor[int]() {
+		t.Errorf("got %v -> %v, want string -> int", assignErr.From, assignErr.To)
+	}
+	var pathErr *valpath.PathError
+	if !errors.As(err, &pathErr) {
+		t.Fatalf("got error %v, want *valpath.PathError", err)
+	}
+	if pathErr.Pos != 1 {
+		t.Errorf("got pos %d, want 1", pathErr.Pos)
+	}
+}
+
+func TestSetVivify(t *testing.T) {
+	type Config struct {
+		Outer   testtypes.OuterPtr
+		Ptrs    map[string]*testtypes.Inner
+		Lists   map[string][]int
+		Slice   []testtypes.Inner
+		IFace   testtypes.IFace
+		Structs map[string]testtypes.OuterPtr
+	}
+	opts := valpath.SetOptions{
+		Vivify: true,
+		Factories: map[reflect.Type]func() reflect.Value{
+			reflect.TypeFor[testtypes.IFace](): func() reflect.Value {
+				return reflect.ValueOf(testtypes.IFaceImpl(0))
+			},
+		},
+	}
+	testCases := []struct {
+		name   string
+		path   string
+		newVal any
+		want   Config
+	}{
+		{
+			name:   "promoted field through nil embedded pointer",
+			path:   "Outer.Int",
+			newVal: 42,
+			want:   Config{Outer: testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 42}}},
+		},
+		{
+			name:   "explicit nil pointer",
+			path:   "(*Outer.Inner).Int",
+			newVal: 42,
+			want:   Config{Outer: testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 42}}},
+		},
+		{
+			name:   "nil map and missing pointer entry",
+			path:   `(*Ptrs["a"]).Int`,
+			newVal: 42,
+			want:   Config{Ptrs: map[string]*testtypes.Inner{"a": {Int: 42}}},
+		},
+		{
+			name:   "slice inside map entry",
+			path:   `Lists["a"][2]`,
+			newVal: 42,
+			want:   Config{Lists: map[string][]int{"a": {0, 0, 42}}},
+		},
+		{
+			name:   "grown slice",
+			path:   "Slice[1].Int",
+			newVal: 42,
+			want:   Config{Slice: []testtypes.Inner{{}, {Int: 42}}},
+		},
+		{
+			name:   "nil interface with factory",
+			path:   "(IFace)",
+			newVal: 42,
+			want:   Config{IFace: testtypes.IFaceImpl(42)},
+		},
+		{
+			name:   "struct-valued map entry with nil embedded pointer",
+			path:   `Structs["a"].Int`,
+			newVal: 42,
+			want:   Config{Structs: map[string]testtypes.OuterPtr{"a": {Inner: &testtypes.Inner{Int: 42}}}},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := valpath.Parse(tt.path)
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			got := Config{}
+			if err := valpath.SetWith(reflect.ValueOf(&got).Elem(), p, reflect.ValueOf(tt.newVal), opts); err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("without vivify", func(t *testing.T) {
+		got := Config{}
+		err := valpath.SetAt(&got, valpath.ExportedField("Int"), 42)
+		if !errors.Is(err, valpath.ErrNoSuchField) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNoSuchField)
+		}
+		err = valpath.SetAt(&got, valpath.Join(valpath.ExportedField("Outer"), valpath.ExportedField("Int")), 42)
+		if !errors.Is(err, valpath.ErrNilPointer) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNilPointer)
+		}
+	})
+
+	t.Run("nil interface without factory", func(t *testing.T) {
+		var got struct{ Any any }
+		err := valpath.SetWith(
+			reflect.ValueOf(&got).Elem(), valpath.Join(valpath.ExportedField("Any"), valpath.Inter()),
+			reflect.ValueOf(42), valpath.SetOptions{Vivify: true})
+		if !errors.Is(err, valpath.ErrNilPointer) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNilPointer)
+		}
+	})
+}
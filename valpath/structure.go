@@ -0,0 +1,68 @@
+package valpath
+
+import (
+	"errors"
+	"iter"
+	"slices"
+)
+
+var ErrNotPrefix = errors.New("path is not a prefix")
+
+// Steps yields the individual steps of p, with nested joins flattened.
+func Steps(p Path) iter.Seq[Path] {
+	return p.elems()
+}
+
+// Len returns the number of steps in p.
+func Len(p Path) int {
+	n := 0
+	for range p.elems() {
+		n++
+	}
+	return n
+}
+
+// Parent returns p without its last step.  The parent of the empty path is the empty path.
+func Parent(p Path) Path {
+	steps := slices.Collect(p.elems())
+	if len(steps) == 0 {
+		return Empty()
+	}
+	return Join(steps[:len(steps)-1]...)
+}
+
+// Last returns the last step of p, or the empty path if p has no steps.
+func Last(p Path) Path {
+	steps := slices.Collect(p.elems())
+	if len(steps) == 0 {
+		return Empty()
+	}
+	return steps[len(steps)-1]
+}
+
+// HasPrefix reports whether the steps of p begin with the steps of prefix.
+func HasPrefix(p, prefix Path) bool {
+	steps := slices.Collect(p.elems())
+	prefixSteps := slices.Collect(prefix.elems())
+	return len(prefixSteps) <= len(steps) && slices.EqualFunc(steps[:len(prefixSteps)], prefixSteps, stepEqual)
+}
+
+// TrimPrefix returns p without the leading prefix.  If p doesn't start with prefix, p is
+// returned unchanged.
+func TrimPrefix(p, prefix Path) Path {
+	rel, err := Rel(prefix, p)
+	if err != nil {
+		return p
+	}
+	return rel
+}
+
+// Rel returns the path that, when joined to base, addresses the same location as target.
+// It fails with ErrNotPrefix if target isn't within base.
+func Rel(base, target Path) (Path, error) {
+	if !HasPrefix(target, base) {
+		return nil, ErrNotPrefix
+	}
+	steps := slices.Collect(target.elems())
+	return Join(steps[Len(base):]...), nil
+}
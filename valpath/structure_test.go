@@ -0,0 +1,102 @@
+package valpath_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+func TestStructure(t *testing.T) {
+	a := valpath.ExportedField("A")
+	b := valpath.Index(1)
+	c := valpath.MapValueOfKey("c")
+	abc := valpath.Join(valpath.Join(a, b), c)
+
+	t.Run("steps", func(t *testing.T) {
+		got := slices.Collect(valpath.Steps(abc))
+		if want := []valpath.Path{a, b, c}; !slices.EqualFunc(got, want, valpath.Equal) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if got := slices.Collect(valpath.Steps(valpath.Empty())); len(got) != 0 {
+			t.Errorf("got %v, want no steps", got)
+		}
+	})
+
+	t.Run("len", func(t *testing.T) {
+		for _, tt := range []struct {
+			p    valpath.Path
+			want int
+		}{
+			{valpath.Empty(), 0},
+			{a, 1},
+			{abc, 3},
+		} {
+			if got := valpath.Len(tt.p); got != tt.want {
+				t.Errorf("got Len(%v) = %d, want %d", tt.p, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("parent and last", func(t *testing.T) {
+		for _, tt := range []struct {
+			p          valpath.Path
+			wantParent valpath.Path
+			wantLast   valpath.Path
+		}{
+			{valpath.Empty(), valpath.Empty(), valpath.Empty()},
+			{a, valpath.Empty(), a},
+			{abc, valpath.Join(a, b), c},
+		} {
+			if got := valpath.Parent(tt.p); !valpath.Equal(got, tt.wantParent) {
+				t.Errorf("got Parent(%v) = %v, want %v", tt.p, got, tt.wantParent)
+			}
+			if got := valpath.Last(tt.p); !valpath.Equal(got, tt.wantLast) {
+				t.Errorf("got Last(%v) = %v, want %v", tt.p, got, tt.wantLast)
+			}
+		}
+	})
+
+	t.Run("prefixes", func(t *testing.T) {
+		for _, tt := range []struct {
+			p, prefix valpath.Path
+			want      bool
+			wantRel   valpath.Path
+		}{
+			{abc, valpath.Empty(), true, abc},
+			{abc, a, true, valpath.Join(b, c)},
+			{abc, valpath.Join(a, b), true, c},
+			{abc, abc, true, valpath.Empty()},
+			{abc, b, false, nil},
+			{a, abc, false, nil},
+			{abc, valpath.Join(a, valpath.MapValueOfKey("c")), false, nil},
+		} {
+			if got := valpath.HasPrefix(tt.p, tt.prefix); got != tt.want {
+				t.Errorf("got HasPrefix(%v, %v) = %v, want %v", tt.p, tt.prefix, got, tt.want)
+			}
+			rel, err := valpath.Rel(tt.prefix, tt.p)
+			trimmed := valpath.TrimPrefix(tt.p, tt.prefix)
+			if !tt.want {
+				if !errors.Is(err, valpath.ErrNotPrefix) {
+					t.Errorf("got Rel(%v, %v) error %v, want %v", tt.prefix, tt.p, err, valpath.ErrNotPrefix)
+				}
+				if !valpath.Equal(trimmed, tt.p) {
+					t.Errorf("got TrimPrefix(%v, %v) = %v, want unchanged", tt.p, tt.prefix, trimmed)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("got Rel(%v, %v) error %v, want no error", tt.prefix, tt.p, err)
+			} else if !valpath.Equal(rel, tt.wantRel) {
+				t.Errorf("got Rel(%v, %v) = %v, want %v", tt.prefix, tt.p, rel, tt.wantRel)
+			}
+			if !valpath.Equal(trimmed, tt.wantRel) {
+				t.Errorf("got TrimPrefix(%v, %v) = %v, want %v", tt.p, tt.prefix, trimmed, tt.wantRel)
+			}
+			if joined := valpath.Join(tt.prefix, rel); !valpath.Equal(joined, tt.p) {
+				t.Errorf("got Join(%v, %v) = %v, want %v", tt.prefix, rel, joined, tt.p)
+			}
+		}
+	})
+}
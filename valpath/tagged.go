@@ -0,0 +1,215 @@
+package valpath
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+func TaggedField(key, name string) Path {
+	return TaggedFieldPart{Key: key, Name: name}
+}
+
+// TaggedFieldPart addresses a struct field by the name given to it by the struct tag Key,
+// such as the json tag.  Fields are matched the same way that encoding/json matches them to
+// JSON object members, so fields without a tag are found by their Go names, and fields of
+// embedded structs are promoted.
+type TaggedFieldPart struct {
+	Key, Name string
+}
+
+func (f TaggedFieldPart) String() string {
+	return fmt.Sprintf("<%s field %s>", f.Key, f.Name)
+}
+
+func (f TaggedFieldPart) Traverse(v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() {
+		return zeroValue, newPathError(f, v, ErrInvalidValue)
+	}
+	if v.Kind() != reflect.Struct {
+		return zeroValue, newPathError(f, v, ErrKindMismatch)
+	}
+	field, ok := taggedFieldByName(v.Type(), f.Key, f.Name)
+	if !ok {
+		return zeroValue, newPathError(f, v, ErrNoSuchField)
+	}
+	fieldValue, err := v.FieldByIndexErr(field.index)
+	if err != nil {
+		return zeroValue, newPathError(f, v, ErrNilPointer)
+	}
+	return fieldValue, nil
+}
+
+func (f TaggedFieldPart) elems() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		yield(f)
+	}
+}
+
+func (f TaggedFieldPart) format(fm *formatter) error {
+	if fm.tagKey != f.Key {
+		return fmt.Errorf("%s tags are not in use", f.Key)
+	}
+	name, err := formatIdent(f.Name)
+	if err != nil {
+		return err
+	}
+	fm.selector(name)
+	return nil
+}
+
+func (f TaggedFieldPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	if _, err := f.Traverse(v); err != nil && !(opts.Vivify && errors.Is(err, ErrNilPointer)) {
+		return slot{}, err
+	}
+	return editField(f, v, f.index(v.Type()), opts)
+}
+
+func (f TaggedFieldPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(f, t, ErrInvalidValue)
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, newTypeError(f, t, ErrKindMismatch)
+	}
+	field, ok := taggedFieldByName(t, f.Key, f.Name)
+	if !ok {
+		return nil, newTypeError(f, t, ErrNoSuchField)
+	}
+	return t.FieldByIndex(field.index).Type, nil
+}
+
+// TagNames returns the names of the fields of struct type t as named by the struct tag key,
+// which are the names that TaggedField will find.
+func TagNames(t reflect.Type, key string) []string {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	var names []string
+	for _, field := range taggedFields(t, key) {
+		names = append(names, field.name)
+	}
+	return names
+}
+
+// index returns the index sequence of the field that f addresses within struct type t.
+func (f TaggedFieldPart) index(t reflect.Type) []int {
+	field, _ := taggedFieldByName(t, f.Key, f.Name)
+	return field.index
+}
+
+// taggedField describes a struct field as named by a struct tag.
+type taggedField struct {
+	name  string
+	index []int
+}
+
+// taggedFields lists the fields of struct type t as named by the struct tag key, following
+// the rules that encoding/json uses for json tags: fields tagged "-" and unexported fields
+// are left out, untagged fields keep their Go names, and the fields of untagged embedded
+// structs are promoted unless a shallower field has the same name.
+func taggedFields(t reflect.Type, key string) []taggedField {
+	type embed struct {
+		t     reflect.Type
+		index []int
+	}
+	var fields []taggedField
+	// taken holds the names claimed at shallower depths, which hide deeper fields.
+	taken := map[string]bool{}
+	visited := map[reflect.Type]bool{}
+	next := []embed{{t: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		byName := map[string][]taggedField{}
+		tagged := map[string][]taggedField{}
+		var names []string
+		for _, e := range current {
+			if visited[e.t] {
+				continue
+			}
+			visited[e.t] = true
+			for i := range e.t.NumField() {
+				fieldDesc := e.t.Field(i)
+				fieldType := fieldDesc.Type
+				if fieldType.Name() == "" && fieldType.Kind() == reflect.Pointer {
+					fieldType = fieldType.Elem()
+				}
+				if fieldDesc.Anonymous {
+					if !fieldDesc.IsExported() && fieldType.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !fieldDesc.IsExported() {
+					continue
+				}
+				tag := fieldDesc.Tag.Get(key)
+				if tag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+				index := append(slices.Clone(e.index), i)
+				if name == "" && fieldDesc.Anonymous && fieldType.Kind() == reflect.Struct {
+					next = append(next, embed{t: fieldType, index: index})
+					continue
+				}
+				field := taggedField{name: name, index: index}
+				if name == "" {
+					field.name = fieldDesc.Name
+				} else {
+					tagged[field.name] = append(tagged[field.name], field)
+				}
+				if _, ok := byName[field.name]; !ok {
+					names = append(names, field.name)
+				}
+				byName[field.name] = append(byName[field.name], field)
+			}
+		}
+		for _, name := range names {
+			if taken[name] {
+				continue
+			}
+			taken[name] = true
+			// Among fields with the same name at the same depth, a single tagged field wins;
+			// otherwise they cancel each other out.
+			switch {
+			case len(byName[name]) == 1:
+				fields = append(fields, byName[name][0])
+			case len(tagged[name]) == 1:
+				fields = append(fields, tagged[name][0])
+			}
+		}
+	}
+	return fields
+}
+
+func taggedFieldByName(t reflect.Type, key, name string) (taggedField, bool) {
+	for _, field := range taggedFields(t, key) {
+		if field.name == name {
+			return field, true
+		}
+	}
+	return taggedField{}, false
+}
+
+func taggedFieldByIndex(t reflect.Type, key string, index []int) (taggedField, bool) {
+	for _, field := range taggedFields(t, key) {
+		if slices.Equal(field.index, index) {
+			return field, true
+		}
+	}
+	return taggedField{}, false
+}
+
+// tagEmbeds reports whether index leads from t to an embedded struct whose fields are
+// promoted into t when naming fields by the struct tag key.
+func tagEmbeds(t reflect.Type, key string, index []int) bool {
+	for _, field := range taggedFields(t, key) {
+		if len(field.index) > len(index) && slices.Equal(field.index[:len(index)], index) {
+			return true
+		}
+	}
+	return false
+}
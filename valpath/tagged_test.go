@@ -0,0 +1,190 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+type taggedItem struct {
+	DisplayName string `json:"display_name" db:"name"`
+	Internal    string `json:"-"`
+	Plain       int
+}
+
+type TaggedBase struct {
+	ID    int    `json:"id"`
+	Owner string `json:"owner"`
+}
+
+type taggedRoot struct {
+	*TaggedBase
+	Items  []taggedItem `json:"items"`
+	Owner  string       `json:"owner_name"`
+	Shadow string       `json:"owner"`
+}
+
+func TestTaggedField(t *testing.T) {
+	in := reflect.ValueOf(taggedRoot{
+		TaggedBase: &TaggedBase{ID: 7, Owner: "base"},
+		Items:      []taggedItem{{DisplayName: "first", Internal: "x", Plain: 1}},
+		Owner:      "root",
+		Shadow:     "shadow",
+	})
+	testCases := []struct {
+		name    string
+		in      reflect.Value
+		path    valpath.Path
+		wantAny any
+		wantErr error
+	}{
+		{
+			name:    "tagged field",
+			in:      in,
+			path:    valpath.Join(valpath.TaggedField("json", "items"), valpath.Index(0), valpath.TaggedField("json", "display_name")),
+			wantAny: "first",
+		},
+		{
+			name:    "other tag key",
+			in:      in,
+			path:    valpath.Join(valpath.TaggedField("json", "items"), valpath.Index(0), valpath.TaggedField("db", "name")),
+			wantAny: "first",
+		},
+		{
+			name:    "untagged field by go name",
+			in:      in,
+			path:    valpath.Join(valpath.TaggedField("json", "items"), valpath.Index(0), valpath.TaggedField("json", "Plain")),
+			wantAny: 1,
+		},
+		{
+			name:    "promoted through embedded pointer",
+			in:      in,
+			path:    valpath.TaggedField("json", "id"),
+			wantAny: 7,
+		},
+		{
+			name:    "shallower field hides promoted field",
+			in:      in,
+			path:    valpath.TaggedField("json", "owner"),
+			wantAny: "shadow",
+		},
+		{
+			name:    "excluded field",
+			in:      in,
+			path:    valpath.Join(valpath.TaggedField("json", "items"), valpath.Index(0), valpath.TaggedField("json", "Internal")),
+			wantErr: valpath.ErrNoSuchField,
+		},
+		{
+			name:    "go name of tagged field",
+			in:      in,
+			path:    valpath.TaggedField("json", "Items"),
+			wantErr: valpath.ErrNoSuchField,
+		},
+		{
+			name:    "nil embedded pointer",
+			in:      reflect.ValueOf(taggedRoot{}),
+			path:    valpath.TaggedField("json", "id"),
+			wantErr: valpath.ErrNilPointer,
+		},
+		{
+			name:    "not a struct",
+			in:      reflect.ValueOf(42),
+			path:    valpath.TaggedField("json", "id"),
+			wantErr: valpath.ErrKindMismatch,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.path.Traverse(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Interface() != tt.wantAny {
+				t.Errorf("got %v, want %v", got.Interface(), tt.wantAny)
+			}
+			gotType, err := valpath.ResolveType(tt.in.Type(), tt.path)
+			if err != nil {
+				t.Fatalf("got ResolveType error %v, want no error", err)
+			}
+			if gotType != got.Type() {
+				t.Errorf("got ResolveType %v, want %v", gotType, got.Type())
+			}
+		})
+	}
+
+	t.Run("set with vivify", func(t *testing.T) {
+		var got taggedRoot
+		err := valpath.SetWith(reflect.ValueOf(&got).Elem(), valpath.TaggedField("json", "id"), reflect.ValueOf(3), valpath.SetOptions{Vivify: true})
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got.TaggedBase == nil || got.ID != 3 {
+			t.Errorf("got %+v, want ID 3", got.TaggedBase)
+		}
+	})
+
+	t.Run("canonicalize", func(t *testing.T) {
+		got, err := valpath.Canonicalize(reflect.TypeFor[taggedRoot](), valpath.TaggedField("json", "id"))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		want := valpath.Join(valpath.ExportedField("TaggedBase"), valpath.Deref(), valpath.ExportedField("ID"))
+		if !valpath.Equal(got, want) {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("tag names", func(t *testing.T) {
+		got := valpath.TagNames(reflect.TypeFor[taggedRoot](), "json")
+		want := []string{"items", "owner_name", "owner", "id"}
+		if !slices.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func TestParseTagged(t *testing.T) {
+	opts := valpath.SyntaxOptions{TagKey: "json"}
+	in := "items[0].display_name"
+	got, err := valpath.ParseWith(in, opts)
+	if err != nil {
+		t.Fatalf("got error %v, want no error", err)
+	}
+	want := valpath.Join(valpath.TaggedField("json", "items"), valpath.Index(0), valpath.TaggedField("json", "display_name"))
+	if !valpath.Equal(got, want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	found, err := got.Traverse(reflect.ValueOf(taggedRoot{Items: []taggedItem{{DisplayName: "first"}}}))
+	if err != nil || found.Interface() != "first" {
+		t.Errorf("got %v, %v, want first", found, err)
+	}
+	formatted, err := valpath.FormatWith(got, opts)
+	if err != nil {
+		t.Fatalf("got error %v, want no error", err)
+	}
+	if formatted != in {
+		t.Errorf("got %q, want %q", formatted, in)
+	}
+
+	for _, tt := range []struct {
+		name string
+		path valpath.Path
+		opts valpath.SyntaxOptions
+	}{
+		{name: "tagged field without tag key", path: want},
+		{name: "tagged field with other tag key", path: want, opts: valpath.SyntaxOptions{TagKey: "db"}},
+		{name: "go field name with tag key", path: valpath.ExportedField("Items"), opts: opts},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := valpath.FormatWith(tt.path, tt.opts); !errors.Is(err, valpath.ErrNotFormattable) {
+				t.Errorf("got error %v, want %v", err, valpath.ErrNotFormattable)
+			}
+		})
+	}
+}
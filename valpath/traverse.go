@@ -0,0 +1,123 @@
+package valpath
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Options controls the behavior of TraverseWith.
+type Options struct {
+	// AutoDeref dereferences non-nil pointers and unwraps non-nil interfaces before steps
+	// that apply to structs, slices, arrays, strings, and maps, much as Go does for selectors
+	// on pointers.
+	AutoDeref bool
+}
+
+// TraverseWith is like Traverse, but with behavior controlled by opts.  It also returns the
+// path that was actually taken, which includes explicit Deref and Inter steps for any
+// pointers and interfaces that were passed through automatically.  Errors are reported
+// relative to that path.
+func TraverseWith(v reflect.Value, p Path, opts Options) (reflect.Value, Path, error) {
+	steps := slices.Collect(p.elems())
+	if !opts.AutoDeref || len(steps) == 0 {
+		got, err := p.Traverse(v)
+		if err != nil {
+			return zeroValue, nil, err
+		}
+		return got, p, nil
+	}
+	var taken []Path
+	var hidden readOnlyTracker
+	apply := func(step Path) error {
+		next, err := step.Traverse(v)
+		if err != nil {
+			return atPos(err, step, typeOf(v), taken, len(taken))
+		}
+		taken = append(taken, step)
+		v = hidden.next(step, next)
+		return nil
+	}
+	for _, step := range steps {
+		for autoDerefs(step) && v.IsValid() {
+			if v.Kind() == reflect.Pointer {
+				if err := apply(Deref()); err != nil {
+					return zeroValue, nil, err
+				}
+			} else if v.Kind() == reflect.Interface {
+				if err := apply(Inter()); err != nil {
+					return zeroValue, nil, err
+				}
+			} else {
+				break
+			}
+		}
+		if err := apply(step); err != nil {
+			return zeroValue, nil, err
+		}
+	}
+	return v, Join(taken...), nil
+}
+
+// autoDerefs reports whether step is preceded by automatic dereferencing, because it can't
+// apply to a pointer or interface itself.
+func autoDerefs(step Path) bool {
+	switch step.(type) {
+	case ExportedFieldPart, UnexportedFieldPart, FieldIndexPart, TaggedFieldPart,
+		IndexPart, FromEndPart, SliceRangePart, ByteAtPart, RuneAtPart,
+		MapKeyPart, MapValueOfKeyPart:
+		return true
+	default:
+		return false
+	}
+}
+
+// TraceStep records the application of a single step during Trace.
+type TraceStep struct {
+	Step Path
+	// In is the value that Step was applied to.
+	In reflect.Value
+	// Out is the value that Step produced, or the zero Value if it failed.
+	Out reflect.Value
+	// Err is the error from Step, positioned within the full path as Traverse would report it.
+	Err error
+}
+
+func (s TraceStep) String() string {
+	if s.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", s.Step, describe(s.In), s.Err)
+	}
+	return fmt.Sprintf("%s: %s -> %s", s.Step, describe(s.In), describe(s.Out))
+}
+
+func describe(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<invalid>"
+	}
+	return fmt.Sprintf("(%s) %v", v.Type(), v)
+}
+
+// Trace traverses p within v like Traverse, but records every step along the way.  If a step
+// fails, it is the last one recorded.  An empty path is recorded as a single Empty step.
+func Trace(v reflect.Value, p Path) []TraceStep {
+	steps := slices.Collect(p.elems())
+	if len(steps) == 0 {
+		steps = []Path{Empty()}
+	}
+	var trace []TraceStep
+	var hidden readOnlyTracker
+	for pos, step := range steps {
+		next, err := step.Traverse(v)
+		if err == nil {
+			next = hidden.next(step, next)
+		}
+		rec := TraceStep{Step: step, In: v, Out: next}
+		if err != nil {
+			rec.Err = atPos(err, step, typeOf(v), steps[:pos], pos)
+			return append(trace, rec)
+		}
+		trace = append(trace, rec)
+		v = next
+	}
+	return trace
+}
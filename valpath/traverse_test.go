@@ -0,0 +1,185 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+func TestTraverseWith(t *testing.T) {
+	type root struct {
+		Outer *testtypes.OuterPtr
+		Any   any
+		Items *[]*testtypes.Inner
+		Names map[string]*string
+		Nil   *testtypes.Inner
+	}
+	name := "x"
+	in := reflect.ValueOf(&root{
+		Outer: &testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 1}},
+		Any:   &testtypes.Inner{Int: 2},
+		Items: &[]*testtypes.Inner{{Int: 3}},
+		Names: map[string]*string{"a": &name},
+	})
+	autoDeref := valpath.Options{AutoDeref: true}
+	testCases := []struct {
+		name      string
+		path      valpath.Path
+		opts      valpath.Options
+		wantAny   any
+		wantTaken valpath.Path
+		wantErr   error
+		wantPos   int
+	}{
+		{
+			name:      "empty",
+			path:      valpath.Empty(),
+			opts:      autoDeref,
+			wantAny:   in.Interface(),
+			wantTaken: valpath.Empty(),
+		},
+		{
+			name:    "without auto deref",
+			path:    valpath.ExportedField("Outer"),
+			wantErr: valpath.ErrKindMismatch,
+			wantPos: 0,
+		},
+		{
+			name:    "pointers",
+			path:    valpath.Join(valpath.ExportedField("Outer"), valpath.ExportedField("Int")),
+			opts:    autoDeref,
+			wantAny: 1,
+			wantTaken: valpath.Join(
+				valpath.Deref(), valpath.ExportedField("Outer"), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name:    "interface holding pointer",
+			path:    valpath.Join(valpath.ExportedField("Any"), valpath.ExportedField("Int")),
+			opts:    autoDeref,
+			wantAny: 2,
+			wantTaken: valpath.Join(
+				valpath.Deref(), valpath.ExportedField("Any"), valpath.Inter(), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name:    "index through pointers",
+			path:    valpath.Join(valpath.ExportedField("Items"), valpath.Index(0), valpath.ExportedField("Int")),
+			opts:    autoDeref,
+			wantAny: 3,
+			wantTaken: valpath.Join(
+				valpath.Deref(), valpath.ExportedField("Items"), valpath.Deref(), valpath.Index(0), valpath.Deref(), valpath.ExportedField("Int")),
+		},
+		{
+			name:      "pointer leaf is kept",
+			path:      valpath.Join(valpath.ExportedField("Names"), valpath.MapValueOfKey("a")),
+			opts:      autoDeref,
+			wantAny:   &name,
+			wantTaken: valpath.Join(valpath.Deref(), valpath.ExportedField("Names"), valpath.MapValueOfKey("a")),
+		},
+		{
+			name:      "explicit deref",
+			path:      valpath.Join(valpath.ExportedField("Names"), valpath.MapValueOfKey("a"), valpath.Deref()),
+			opts:      autoDeref,
+			wantAny:   "x",
+			wantTaken: valpath.Join(valpath.Deref(), valpath.ExportedField("Names"), valpath.MapValueOfKey("a"), valpath.Deref()),
+		},
+		{
+			name:    "nil pointer",
+			path:    valpath.Join(valpath.ExportedField("Nil"), valpath.ExportedField("Int")),
+			opts:    autoDeref,
+			wantErr: valpath.ErrNilPointer,
+			wantPos: 2,
+		},
+		{
+			name:    "missing field",
+			path:    valpath.Join(valpath.ExportedField("Outer"), valpath.ExportedField("Missing")),
+			opts:    autoDeref,
+			wantErr: valpath.ErrNoSuchField,
+			wantPos: 3,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, taken, err := valpath.TraverseWith(in, tt.path, tt.opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				var pathErr *valpath.PathError
+				if !errors.As(err, &pathErr) || pathErr.Pos != tt.wantPos {
+					t.Errorf("got error %v, want error at step %d", err, tt.wantPos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.wantAny) {
+				t.Errorf("got %v, want %v", got.Interface(), tt.wantAny)
+			}
+			if !valpath.Equal(taken, tt.wantTaken) {
+				t.Errorf("got path %s, want %s", taken, tt.wantTaken)
+			}
+			again, err := taken.Traverse(in)
+			if err != nil {
+				t.Fatalf("got error %v retraversing, want no error", err)
+			}
+			if again.Interface() != got.Interface() {
+				t.Errorf("got %v retraversing, want %v", again.Interface(), got.Interface())
+			}
+		})
+	}
+}
+
+func TestTrace(t *testing.T) {
+	type root struct {
+		Outer testtypes.OuterPtr
+		Tags  map[string]int
+	}
+	in := reflect.ValueOf(root{Tags: map[string]int{"a": 1}})
+
+	t.Run("success", func(t *testing.T) {
+		path := valpath.Join(valpath.ExportedField("Tags"), valpath.MapValueOfKey("a"))
+		trace := valpath.Trace(in, path)
+		if len(trace) != 2 {
+			t.Fatalf("got %d steps, want 2", len(trace))
+		}
+		if !valpath.Equal(trace[0].Step, valpath.ExportedField("Tags")) || trace[0].In.Type() != in.Type() {
+			t.Errorf("got first step %v, want Tags applied to root", trace[0])
+		}
+		if trace[1].In.Len() != 1 || trace[1].Out.Interface() != 1 || trace[1].Err != nil {
+			t.Errorf("got second step %v, want map -> 1", trace[1])
+		}
+		if got, want := trace[1].String(), `<map value of key a>: (map[string]int) map[a:1] -> (int) 1`; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		path := valpath.Join(valpath.ExportedField("Outer"), valpath.ExportedField("Inner"), valpath.Deref(), valpath.ExportedField("Int"))
+		trace := valpath.Trace(in, path)
+		if len(trace) != 3 {
+			t.Fatalf("got %d steps, want 3", len(trace))
+		}
+		last := trace[2]
+		if !errors.Is(last.Err, valpath.ErrNilPointer) {
+			t.Errorf("got error %v, want %v", last.Err, valpath.ErrNilPointer)
+		}
+		var pathErr *valpath.PathError
+		if !errors.As(last.Err, &pathErr) || pathErr.Pos != 2 {
+			t.Errorf("got error %v, want error at step 2", last.Err)
+		}
+		if !last.In.IsNil() || last.Out.IsValid() {
+			t.Errorf("got step %v, want nil input and no output", last)
+		}
+	})
+
+	t.Run("empty path", func(t *testing.T) {
+		trace := valpath.Trace(reflect.Value{}, valpath.Empty())
+		if len(trace) != 1 || !errors.Is(trace[0].Err, valpath.ErrInvalidValue) {
+			t.Errorf("got %v, want a single invalid value error", trace)
+		}
+	})
+}
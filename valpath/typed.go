@@ -0,0 +1,91 @@
+package valpath
+
+import "reflect"
+
+// Get returns the value addressed by p within root as a T.  The value must be assignable to
+// T, as with a type assertion; it is not converted.
+func Get[T any](root any, p Path) (T, error) {
+	var out T
+	v, err := p.Traverse(reflect.ValueOf(root))
+	if err != nil {
+		return out, err
+	}
+	if err := getInto(reflect.ValueOf(&out).Elem(), v); err != nil {
+		return out, leafError(p, v.Type(), err)
+	}
+	return out, nil
+}
+
+// MustGet is like Get but panics if the value can't be retrieved.
+func MustGet[T any](root any, p Path) T {
+	out, err := Get[T](root, p)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func getInto(dst, src reflect.Value) error {
+	if !src.Type().AssignableTo(dst.Type()) {
+		return &AssignError{From: src.Type(), To: dst.Type()}
+	}
+	dst.Set(src)
+	return nil
+}
+
+// Typed is a Path that has been checked against values of type Root and found to address a
+// value of type Leaf, so that it can be used without reflection.
+type Typed[Root, Leaf any] struct {
+	a *Accessor
+}
+
+// NewTyped compiles p against Root, and checks that the value it addresses is assignable to
+// Leaf.  If that value's type depends on the dynamic type of an interface, the check is made
+// on each call to Get instead.
+func NewTyped[Root, Leaf any](p Path) (*Typed[Root, Leaf], error) {
+	a, err := Compile(reflect.TypeFor[Root](), p)
+	if err != nil {
+		return nil, err
+	}
+	leaf := reflect.TypeFor[Leaf]()
+	if a.Type() != Dynamic && !a.Type().AssignableTo(leaf) {
+		return nil, leafError(p, a.Type(), &AssignError{From: a.Type(), To: leaf})
+	}
+	return &Typed[Root, Leaf]{a: a}, nil
+}
+
+// MustNewTyped is like NewTyped but panics if p doesn't address a Leaf within a Root.
+func MustNewTyped[Root, Leaf any](p Path) *Typed[Root, Leaf] {
+	t, err := NewTyped[Root, Leaf](p)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func (t *Typed[Root, Leaf]) Path() Path {
+	return t.a.Path()
+}
+
+// Get returns the value that t addresses within root.
+func (t *Typed[Root, Leaf]) Get(root Root) (Leaf, error) {
+	var out Leaf
+	v, err := t.a.Get(reflect.ValueOf(&root).Elem())
+	if err != nil {
+		return out, err
+	}
+	if err := getInto(reflect.ValueOf(&out).Elem(), v); err != nil {
+		return out, leafError(t.a.Path(), v.Type(), err)
+	}
+	return out, nil
+}
+
+// Set stores v at the location that t addresses within *root.
+func (t *Typed[Root, Leaf]) Set(root *Root, v Leaf) error {
+	newVal := reflect.ValueOf(&v).Elem()
+	if newVal.Kind() == reflect.Interface {
+		// Store the dynamic value, which may suit a location of a narrower type than Leaf.
+		newVal = newVal.Elem()
+	}
+	return t.a.Set(reflect.ValueOf(root).Elem(), newVal)
+}
@@ -0,0 +1,150 @@
+package valpath_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+func TestGet(t *testing.T) {
+	root := newSetRoot()
+	root.Int = 7
+
+	t.Run("concrete", func(t *testing.T) {
+		got, err := valpath.Get[int](root, valpath.Join(valpath.Deref(), valpath.ExportedField("Int")))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got != 7 {
+			t.Errorf("got %d, want 7", got)
+		}
+	})
+
+	t.Run("interface", func(t *testing.T) {
+		got, err := valpath.Get[testtypes.IFace](*root, valpath.ExportedField("IFace"))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got != testtypes.IFaceImpl(1) {
+			t.Errorf("got %v, want %v", got, testtypes.IFaceImpl(1))
+		}
+	})
+
+	t.Run("nil interface", func(t *testing.T) {
+		got, err := valpath.Get[any](setRoot{}, valpath.ExportedField("Any"))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got != nil {
+			t.Errorf("got %v, want nil", got)
+		}
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		_, err := valpath.Get[string](*root, valpath.ExportedField("Int"))
+		var assignErr *valpath.AssignError
+		if !errors.As(err, &assignErr) {
+			t.Fatalf("got error %v, want an AssignError", err)
+		}
+		var pathErr *valpath.PathError
+		if !errors.As(err, &pathErr) || pathErr.Pos != 0 {
+			t.Errorf("got error %v, want error at step 0", err)
+		}
+	})
+
+	t.Run("traverse error", func(t *testing.T) {
+		_, err := valpath.Get[int](*root, valpath.ExportedField("Missing"))
+		if !errors.Is(err, valpath.ErrNoSuchField) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNoSuchField)
+		}
+	})
+
+	t.Run("must", func(t *testing.T) {
+		if got := valpath.MustGet[int](*root, valpath.ExportedField("Int")); got != 7 {
+			t.Errorf("got %d, want 7", got)
+		}
+		defer func() {
+			if recover() == nil {
+				t.Error("got no panic, want panic")
+			}
+		}()
+		valpath.MustGet[string](*root, valpath.ExportedField("Int"))
+	})
+}
+
+func TestTyped(t *testing.T) {
+	t.Run("get and set", func(t *testing.T) {
+		typed, err := valpath.NewTyped[setRoot, int](valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Int")))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		root := newSetRoot()
+		if err := typed.Set(root, 42); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		got, err := typed.Get(*root)
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got != 42 {
+			t.Errorf("got %d, want 42", got)
+		}
+	})
+
+	t.Run("interface leaf", func(t *testing.T) {
+		typed := valpath.MustNewTyped[setRoot, any](valpath.ExportedField("Int"))
+		root := newSetRoot()
+		if err := typed.Set(root, 42); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if root.Int != 42 {
+			t.Errorf("got %d, want 42", root.Int)
+		}
+		if err := typed.Set(root, nil); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if root.Int != 0 {
+			t.Errorf("got %d, want 0", root.Int)
+		}
+	})
+
+	t.Run("dynamic leaf", func(t *testing.T) {
+		typed, err := valpath.NewTyped[setRoot, int](valpath.Join(
+			valpath.ExportedField("Any"), valpath.Inter(), valpath.Deref(), valpath.ExportedField("Int")))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		got, err := typed.Get(*newSetRoot())
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got != 1 {
+			t.Errorf("got %d, want 1", got)
+		}
+	})
+
+	t.Run("wrong leaf type", func(t *testing.T) {
+		_, err := valpath.NewTyped[setRoot, string](valpath.ExportedField("Int"))
+		if !errors.Is(err, valpath.ErrNotAssignable) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNotAssignable)
+		}
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		_, err := valpath.NewTyped[setRoot, int](valpath.ExportedField("Missing"))
+		if !errors.Is(err, valpath.ErrNoSuchField) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNoSuchField)
+		}
+	})
+
+	t.Run("must panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("got no panic, want panic")
+			}
+		}()
+		valpath.MustNewTyped[setRoot, string](valpath.ExportedField("Int"))
+	})
+}
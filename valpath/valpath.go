@@ -6,13 +6,14 @@ import (
 	"iter"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
+	"unicode/utf8"
+	"unsafe"
 
 	"github.com/krelinga/go-iters"
 )
 
-var ErrTodo = errors.New("TODO: define a better error for this")
-
 var zeroValue = reflect.Value{}
 
 type Path interface {
@@ -21,6 +22,9 @@ type Path interface {
 	Traverse(reflect.Value) (reflect.Value, error)
 
 	elems() iter.Seq[Path]
+	format(*formatter) error
+	edit(reflect.Value, *SetOptions) (slot, error)
+	resolveType(reflect.Type) (reflect.Type, error)
 }
 
 func Join(children ...Path) Path {
@@ -53,7 +57,7 @@ func (e emptyPathElem) String() string {
 
 func (e emptyPathElem) Traverse(v reflect.Value) (reflect.Value, error) {
 	if !v.IsValid() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(e, v, ErrInvalidValue)
 	}
 	return v, nil
 }
@@ -62,6 +66,26 @@ func (e emptyPathElem) elems() iter.Seq[Path] {
 	return iters.Empty[Path]()
 }
 
+func (e emptyPathElem) format(f *formatter) error {
+	return nil
+}
+
+func (e emptyPathElem) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	if !v.IsValid() {
+		return slot{}, newPathError(e, v, ErrInvalidValue)
+	}
+	return slot{v: v}, nil
+}
+
+func (e emptyPathElem) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(e, t, ErrInvalidValue)
+	}
+	return t, nil
+}
+
+// pathListElem holds single steps, since Join flattens its children, so the prefix before the
+// step at pos is p[:pos].
 type pathListElem []Path
 
 func (p pathListElem) String() string {
@@ -80,12 +104,13 @@ func (p pathListElem) String() string {
 }
 
 func (p pathListElem) Traverse(v reflect.Value) (reflect.Value, error) {
-	for elem := range p.elems() {
-		if val, err := elem.Traverse(v); err != nil {
-			return zeroValue, ErrTodo
-		} else {
-			v = val
+	var hidden readOnlyTracker
+	for pos, elem := range p {
+		val, err := elem.Traverse(v)
+		if err != nil {
+			return zeroValue, atPos(err, elem, typeOf(v), p[:pos], pos)
 		}
+		v = hidden.next(elem, val)
 	}
 	return v, nil
 }
@@ -98,6 +123,69 @@ func (p pathListElem) elems() iter.Seq[Path] {
 	return iters.Concat(children...)
 }
 
+func (p pathListElem) format(f *formatter) error {
+	for elem := range p.elems() {
+		if err := elem.format(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p pathListElem) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	parents := make([]reflect.Value, 0, len(p))
+	slots := make([]slot, 0, len(p))
+	indirect := false
+	for pos, elem := range p {
+		unshared := false
+		if opts.copyOnWrite {
+			var err error
+			if unshared, err = unshare(v); err != nil {
+				return slot{}, atPos(err, elem, typeOf(v), p[:pos], pos)
+			}
+		}
+		s, err := elem.edit(v, opts)
+		if err != nil {
+			return slot{}, atPos(err, elem, typeOf(v), p[:pos], pos)
+		}
+		s.vivified = s.vivified || unshared
+		parents = append(parents, v)
+		slots = append(slots, s)
+		indirect = indirect || s.indirect
+		v = s.v
+	}
+	vivified := false
+	for i := len(slots) - 1; i >= 0; i-- {
+		vivified = (vivified && !slots[i].indirect) || slots[i].vivified
+	}
+	store := func() error {
+		// dirty tracks whether the child of the step being considered has been modified,
+		// and therefore needs to be stored back into its parent.
+		dirty := true
+		for i := len(slots) - 1; i >= 0; i-- {
+			if dirty && slots[i].store != nil {
+				if err := slots[i].store(); err != nil {
+					return atPos(err, p[i], typeOf(parents[i]), p[:i], i)
+				}
+			}
+			dirty = (dirty && !slots[i].indirect) || slots[i].vivified
+		}
+		return nil
+	}
+	return slot{v: v, store: store, indirect: indirect, vivified: vivified}, nil
+}
+
+func (p pathListElem) resolveType(t reflect.Type) (reflect.Type, error) {
+	for pos, elem := range p {
+		next, err := elem.resolveType(t)
+		if err != nil {
+			return nil, atPos(err, elem, t, p[:pos], pos)
+		}
+		t = next
+	}
+	return t, nil
+}
+
 func Deref() Path {
 	return DerefPart{}
 }
@@ -110,13 +198,13 @@ func (d DerefPart) String() string {
 
 func (d DerefPart) Traverse(v reflect.Value) (reflect.Value, error) {
 	if !v.IsValid() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(d, v, ErrInvalidValue)
 	}
 	if v.Kind() != reflect.Pointer {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(d, v, ErrKindMismatch)
 	}
 	if v.IsNil() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(d, v, ErrNilPointer)
 	}
 	return v.Elem(), nil
 }
@@ -127,6 +215,82 @@ func (d DerefPart) elems() iter.Seq[Path] {
 	}
 }
 
+func (d DerefPart) format(f *formatter) error {
+	f.prefix("*")
+	return nil
+}
+
+func (d DerefPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	vivified := false
+	if opts.Vivify && v.Kind() == reflect.Pointer && v.IsNil() && v.CanSet() {
+		v.Set(reflect.New(v.Type().Elem()))
+		vivified = true
+	}
+	elem, err := d.Traverse(v)
+	if err != nil {
+		return slot{}, err
+	}
+	return slot{v: elem, indirect: true, vivified: vivified}, nil
+}
+
+func (d DerefPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(d, t, ErrInvalidValue)
+	}
+	if t.Kind() != reflect.Pointer {
+		return nil, newTypeError(d, t, ErrKindMismatch)
+	}
+	return t.Elem(), nil
+}
+
+// Addr takes the address of the current value, like &x in Go.  The value must be
+// addressable; PointerTo explains why a location isn't.
+func Addr() Path {
+	return AddrPart{}
+}
+
+type AddrPart struct{}
+
+func (a AddrPart) String() string {
+	return "<addr>"
+}
+
+func (a AddrPart) Traverse(v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() {
+		return zeroValue, newPathError(a, v, ErrInvalidValue)
+	}
+	if !v.CanAddr() {
+		return zeroValue, newPathError(a, v, ErrNotAddressable)
+	}
+	return v.Addr(), nil
+}
+
+func (a AddrPart) elems() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		yield(a)
+	}
+}
+
+func (a AddrPart) format(f *formatter) error {
+	f.prefix("&")
+	return nil
+}
+
+func (a AddrPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	ptr, err := a.Traverse(v)
+	if err != nil {
+		return slot{}, err
+	}
+	return slot{v: ptr, indirect: true}, nil
+}
+
+func (a AddrPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(a, t, ErrInvalidValue)
+	}
+	return reflect.PointerTo(t), nil
+}
+
 func Inter() Path {
 	return InterPart{}
 }
@@ -139,13 +303,13 @@ func (i InterPart) String() string {
 
 func (i InterPart) Traverse(v reflect.Value) (reflect.Value, error) {
 	if !v.IsValid() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(i, v, ErrInvalidValue)
 	}
 	if v.Kind() != reflect.Interface {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(i, v, ErrKindMismatch)
 	}
 	if v.IsNil() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(i, v, ErrNilPointer)
 	}
 	return v.Elem(), nil
 }
@@ -156,6 +320,149 @@ func (i InterPart) elems() iter.Seq[Path] {
 	}
 }
 
+func (i InterPart) format(f *formatter) error {
+	f.paren()
+	return nil
+}
+
+func (i InterPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	vivified := false
+	if opts.Vivify && v.Kind() == reflect.Interface && v.IsNil() && v.CanSet() {
+		if factory, ok := opts.Factories[v.Type()]; ok {
+			made := factory()
+			if !made.IsValid() || !made.Type().AssignableTo(v.Type()) {
+				return slot{}, newPathError(i, v, &AssignError{From: typeOf(made), To: v.Type()})
+			}
+			v.Set(made)
+			vivified = true
+		}
+	}
+	elem, err := i.Traverse(v)
+	if err != nil {
+		return slot{}, err
+	}
+	copied := reflect.New(elem.Type()).Elem()
+	copied.Set(elem)
+	store := func() error {
+		if !v.CanSet() {
+			return newPathError(i, v, ErrNotAddressable)
+		}
+		v.Set(copied)
+		return nil
+	}
+	return slot{v: copied, store: store, vivified: vivified}, nil
+}
+
+func (i InterPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(i, t, ErrInvalidValue)
+	}
+	if t.Kind() != reflect.Interface {
+		return nil, newTypeError(i, t, ErrKindMismatch)
+	}
+	return nil, newTypeError(i, t, errDynamic)
+}
+
+func As[T any]() Path {
+	return AsType(reflect.TypeFor[T]())
+}
+
+func AsType(t reflect.Type) Path {
+	return AsTypePart{Type: t}
+}
+
+// AsTypePart is a type assertion.  Like x.(T) in Go, it unwraps an interface value, and
+// succeeds only if the value inside has type Type or, if Type is an interface, implements
+// it.  It also accepts values that aren't interfaces, checking them the same way.  Asserting
+// an interface type produces a value of that interface type.
+type AsTypePart struct {
+	Type reflect.Type
+}
+
+func (a AsTypePart) String() string {
+	return fmt.Sprintf("<as %s>", a.Type)
+}
+
+func (a AsTypePart) Traverse(v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() || a.Type == nil {
+		return zeroValue, newPathError(a, v, ErrInvalidValue)
+	}
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return zeroValue, newPathError(a, v, ErrNilPointer)
+		}
+		v = v.Elem()
+	}
+	if !a.matches(v.Type()) {
+		return zeroValue, newPathError(a, v, ErrTypeMismatch)
+	}
+	if a.Type.Kind() == reflect.Interface {
+		return v.Convert(a.Type), nil
+	}
+	return v, nil
+}
+
+func (a AsTypePart) matches(t reflect.Type) bool {
+	if a.Type.Kind() == reflect.Interface {
+		return t.Implements(a.Type)
+	}
+	return t == a.Type
+}
+
+func (a AsTypePart) elems() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		yield(a)
+	}
+}
+
+func (a AsTypePart) format(f *formatter) error {
+	if a.Type == nil || formattableKeyType[a.Type.Name()] != a.Type {
+		return fmt.Errorf("type %s is not a predeclared type", a.Type)
+	}
+	f.postfix(".(" + a.Type.Name() + ")")
+	return nil
+}
+
+// edit produces a copy of the value inside an interface, which is stored back into the
+// interface, as for Inter.  An interface asserted to another interface type is edited in place.
+func (a AsTypePart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	elem, err := a.Traverse(v)
+	if err != nil {
+		return slot{}, err
+	}
+	if v.Kind() != reflect.Interface || a.Type.Kind() == reflect.Interface {
+		return slot{v: v}, nil
+	}
+	copied := reflect.New(elem.Type()).Elem()
+	copied.Set(elem)
+	store := func() error {
+		if !v.CanSet() {
+			return newPathError(a, v, ErrNotAddressable)
+		}
+		v.Set(copied)
+		return nil
+	}
+	return slot{v: copied, store: store}, nil
+}
+
+// resolveType rejects assertions that could never succeed, and otherwise returns the asserted
+// type, so that the steps after an assertion can be checked statically.
+func (a AsTypePart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil || a.Type == nil {
+		return nil, newTypeError(a, t, ErrInvalidValue)
+	}
+	if t.Kind() == reflect.Interface {
+		if a.Type.Kind() != reflect.Interface && !a.Type.Implements(t) {
+			return nil, newTypeError(a, t, ErrTypeMismatch)
+		}
+		return a.Type, nil
+	}
+	if !a.matches(t) {
+		return nil, newTypeError(a, t, ErrTypeMismatch)
+	}
+	return a.Type, nil
+}
+
 func Index(i int) Path {
 	return IndexPart(i)
 }
@@ -168,15 +475,15 @@ func (i IndexPart) String() string {
 
 func (i IndexPart) Traverse(v reflect.Value) (reflect.Value, error) {
 	if !v.IsValid() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(i, v, ErrInvalidValue)
 	}
 	switch v.Kind() {
 	case reflect.Array, reflect.Slice:
 	default:
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(i, v, ErrKindMismatch)
 	}
 	if i < 0 || i >= IndexPart(v.Len()) {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(i, v, ErrIndexOutOfRange)
 	}
 	return v.Index(int(i)), nil
 }
@@ -187,10 +494,387 @@ func (i IndexPart) elems() iter.Seq[Path] {
 	}
 }
 
+func (i IndexPart) format(f *formatter) error {
+	if i < 0 {
+		return fmt.Errorf("negative index %d", i)
+	}
+	f.postfix(fmt.Sprintf("[%d]", i))
+	return nil
+}
+
+func (i IndexPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	vivified := false
+	if opts.Vivify && v.Kind() == reflect.Slice && i >= IndexPart(v.Len()) && v.CanSet() {
+		v.Grow(int(i) + 1 - v.Len())
+		v.SetLen(int(i) + 1)
+		vivified = true
+	}
+	elem, err := i.Traverse(v)
+	if err != nil {
+		return slot{}, err
+	}
+	return slot{v: elem, indirect: v.Kind() == reflect.Slice, vivified: vivified}, nil
+}
+
+func (i IndexPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(i, t, ErrInvalidValue)
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+	case reflect.Array:
+		if i >= IndexPart(t.Len()) {
+			return nil, newTypeError(i, t, ErrIndexOutOfRange)
+		}
+	default:
+		return nil, newTypeError(i, t, ErrKindMismatch)
+	}
+	if i < 0 {
+		return nil, newTypeError(i, t, ErrIndexOutOfRange)
+	}
+	return t.Elem(), nil
+}
+
+// FromEnd addresses elements counting back from the end of a slice or array, so that
+// FromEnd(1) is the last element.
+func FromEnd(n int) Path {
+	return FromEndPart(n)
+}
+
+type FromEndPart int
+
+func (n FromEndPart) String() string {
+	return fmt.Sprintf("<index %d from end>", n)
+}
+
+func (n FromEndPart) Traverse(v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() {
+		return zeroValue, newPathError(n, v, ErrInvalidValue)
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+	default:
+		return zeroValue, newPathError(n, v, ErrKindMismatch)
+	}
+	if n < 1 || n > FromEndPart(v.Len()) {
+		return zeroValue, newPathError(n, v, ErrIndexOutOfRange)
+	}
+	return v.Index(v.Len() - int(n)), nil
+}
+
+func (n FromEndPart) elems() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		yield(n)
+	}
+}
+
+func (n FromEndPart) format(f *formatter) error {
+	if n < 1 {
+		return fmt.Errorf("index %d from end is less than 1", n)
+	}
+	f.postfix(fmt.Sprintf("[-%d]", n))
+	return nil
+}
+
+func (n FromEndPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	elem, err := n.Traverse(v)
+	if err != nil {
+		return slot{}, err
+	}
+	return slot{v: elem, indirect: v.Kind() == reflect.Slice}, nil
+}
+
+func (n FromEndPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(n, t, ErrInvalidValue)
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+	case reflect.Array:
+		if n > FromEndPart(t.Len()) {
+			return nil, newTypeError(n, t, ErrIndexOutOfRange)
+		}
+	default:
+		return nil, newTypeError(n, t, ErrKindMismatch)
+	}
+	if n < 1 {
+		return nil, newTypeError(n, t, ErrIndexOutOfRange)
+	}
+	return t.Elem(), nil
+}
+
+// SliceRange addresses the elements from lo up to but not including hi, like the slice
+// expression s[lo:hi].  It applies to slices, strings, and arrays that are addressable, and
+// produces a slice or string.
+func SliceRange(lo, hi int) Path {
+	return SliceRangePart{Lo: lo, Hi: hi}
+}
+
+type SliceRangePart struct {
+	Lo, Hi int
+}
+
+func (r SliceRangePart) String() string {
+	return fmt.Sprintf("<slice range %d:%d>", r.Lo, r.Hi)
+}
+
+func (r SliceRangePart) Traverse(v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() {
+		return zeroValue, newPathError(r, v, ErrInvalidValue)
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.String:
+	case reflect.Array:
+		if !v.CanAddr() {
+			return zeroValue, newPathError(r, v, ErrNotAddressable)
+		}
+	default:
+		return zeroValue, newPathError(r, v, ErrKindMismatch)
+	}
+	if r.Lo < 0 || r.Lo > r.Hi || r.Hi > v.Len() {
+		return zeroValue, newPathError(r, v, ErrIndexOutOfRange)
+	}
+	return v.Slice(r.Lo, r.Hi), nil
+}
+
+func (r SliceRangePart) elems() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		yield(r)
+	}
+}
+
+func (r SliceRangePart) format(f *formatter) error {
+	if r.Lo < 0 || r.Lo > r.Hi {
+		return fmt.Errorf("invalid slice range %d:%d", r.Lo, r.Hi)
+	}
+	f.postfix(fmt.Sprintf("[%d:%d]", r.Lo, r.Hi))
+	return nil
+}
+
+// edit returns a copy of the range, which is copied back into place by store.  A string
+// range may be replaced by a string of a different length, but a slice or array range may
+// only be replaced by the same number of elements.
+func (r SliceRangePart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	sub, err := r.Traverse(v)
+	if err != nil {
+		return slot{}, err
+	}
+	copied := reflect.New(sub.Type()).Elem()
+	copied.Set(sub)
+	if v.Kind() == reflect.String {
+		store := func() error {
+			if !v.CanSet() {
+				return newPathError(r, v, ErrNotAddressable)
+			}
+			s := v.String()
+			v.SetString(s[:r.Lo] + copied.String() + s[r.Hi:])
+			return nil
+		}
+		return slot{v: copied, store: store}, nil
+	}
+	store := func() error {
+		if copied.Len() != sub.Len() {
+			err := fmt.Errorf("%w: cannot replace %d elements with %d", ErrNotAssignable, sub.Len(), copied.Len())
+			return newPathError(r, v, err)
+		}
+		reflect.Copy(sub, copied)
+		return nil
+	}
+	// The elements of a range of an array alias the array, so the array has to be treated
+	// as modified even when later steps reach those elements through the slice.
+	isArray := v.Kind() == reflect.Array
+	return slot{v: copied, store: store, indirect: !isArray, vivified: isArray}, nil
+}
+
+func (r SliceRangePart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(r, t, ErrInvalidValue)
+	}
+	if r.Lo < 0 || r.Lo > r.Hi {
+		return nil, newTypeError(r, t, ErrIndexOutOfRange)
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.String:
+		return t, nil
+	case reflect.Array:
+		if r.Hi > t.Len() {
+			return nil, newTypeError(r, t, ErrIndexOutOfRange)
+		}
+		return reflect.SliceOf(t.Elem()), nil
+	default:
+		return nil, newTypeError(r, t, ErrKindMismatch)
+	}
+}
+
+// ByteAt addresses the byte at index i of a string, like the index expression s[i].
+func ByteAt(i int) Path {
+	return ByteAtPart(i)
+}
+
+type ByteAtPart int
+
+func (i ByteAtPart) String() string {
+	return fmt.Sprintf("<byte %d>", i)
+}
+
+func (i ByteAtPart) Traverse(v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() {
+		return zeroValue, newPathError(i, v, ErrInvalidValue)
+	}
+	if v.Kind() != reflect.String {
+		return zeroValue, newPathError(i, v, ErrKindMismatch)
+	}
+	if i < 0 || i >= ByteAtPart(v.Len()) {
+		return zeroValue, newPathError(i, v, ErrIndexOutOfRange)
+	}
+	return reflect.ValueOf(v.String()[i]), nil
+}
+
+func (i ByteAtPart) elems() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		yield(i)
+	}
+}
+
+func (i ByteAtPart) format(f *formatter) error {
+	if i < 0 {
+		return fmt.Errorf("negative index %d", i)
+	}
+	f.conversion("[]byte", fmt.Sprintf("[%d]", i))
+	return nil
+}
+
+func (i ByteAtPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	b, err := i.Traverse(v)
+	if err != nil {
+		return slot{}, err
+	}
+	return editString(i, v, b, int(i), 1), nil
+}
+
+func (i ByteAtPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(i, t, ErrInvalidValue)
+	}
+	if t.Kind() != reflect.String {
+		return nil, newTypeError(i, t, ErrKindMismatch)
+	}
+	if i < 0 {
+		return nil, newTypeError(i, t, ErrIndexOutOfRange)
+	}
+	return reflect.TypeFor[byte](), nil
+}
+
+// RuneAt addresses the i-th code point of a string, as decoded by a range loop over the
+// string, rather than the rune starting at byte offset i.
+func RuneAt(i int) Path {
+	return RuneAtPart(i)
+}
+
+type RuneAtPart int
+
+func (i RuneAtPart) String() string {
+	return fmt.Sprintf("<rune %d>", i)
+}
+
+func (i RuneAtPart) Traverse(v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() {
+		return zeroValue, newPathError(i, v, ErrInvalidValue)
+	}
+	if v.Kind() != reflect.String {
+		return zeroValue, newPathError(i, v, ErrKindMismatch)
+	}
+	r, _, _, ok := runeAt(v.String(), int(i))
+	if !ok {
+		return zeroValue, newPathError(i, v, ErrIndexOutOfRange)
+	}
+	return reflect.ValueOf(r), nil
+}
+
+// runeAt returns the i-th code point of s, along with its byte offset and encoded size.
+func runeAt(s string, i int) (r rune, offset, size int, ok bool) {
+	if i < 0 {
+		return 0, 0, 0, false
+	}
+	n := 0
+	for offset, r := range s {
+		if n == i {
+			_, size := utf8.DecodeRuneInString(s[offset:])
+			return r, offset, size, true
+		}
+		n++
+	}
+	return 0, 0, 0, false
+}
+
+func (i RuneAtPart) elems() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		yield(i)
+	}
+}
+
+func (i RuneAtPart) format(f *formatter) error {
+	if i < 0 {
+		return fmt.Errorf("negative index %d", i)
+	}
+	f.conversion("[]rune", fmt.Sprintf("[%d]", i))
+	return nil
+}
+
+func (i RuneAtPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	r, err := i.Traverse(v)
+	if err != nil {
+		return slot{}, err
+	}
+	_, offset, size, _ := runeAt(v.String(), int(i))
+	return editString(i, v, r, offset, size), nil
+}
+
+func (i RuneAtPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(i, t, ErrInvalidValue)
+	}
+	if t.Kind() != reflect.String {
+		return nil, newTypeError(i, t, ErrKindMismatch)
+	}
+	if i < 0 {
+		return nil, newTypeError(i, t, ErrIndexOutOfRange)
+	}
+	return reflect.TypeFor[rune](), nil
+}
+
+// editString returns a slot holding a copy of c, a byte or rune taken from the string v at
+// offset.  Storing it replaces the size bytes at offset with the encoding of the copy.
+func editString(step Path, v, c reflect.Value, offset, size int) slot {
+	copied := reflect.New(c.Type()).Elem()
+	copied.Set(c)
+	store := func() error {
+		if !v.CanSet() {
+			return newPathError(step, v, ErrNotAddressable)
+		}
+		var encoded string
+		if copied.Kind() == reflect.Uint8 {
+			encoded = string([]byte{byte(copied.Uint())})
+		} else {
+			encoded = string(rune(copied.Int()))
+		}
+		s := v.String()
+		v.SetString(s[:offset] + encoded + s[offset+size:])
+		return nil
+	}
+	return slot{v: copied, store: store}
+}
+
 func MapKey[K comparable](k K) Path {
 	return MapKeyPart(reflect.ValueOf(k))
 }
 
+// MapKeyOf is like MapKey, but takes a key that is already a reflect.Value, such as one
+// returned by reflect.Value.MapKeys.  A key held in an interface is unwrapped.
+func MapKeyOf(k reflect.Value) Path {
+	return MapKeyPart(unwrapKey(k))
+}
+
 type MapKeyPart reflect.Value
 
 func (m MapKeyPart) String() string {
@@ -199,26 +883,26 @@ func (m MapKeyPart) String() string {
 
 func (m MapKeyPart) Traverse(v reflect.Value) (reflect.Value, error) {
 	if !v.IsValid() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(m, v, ErrInvalidValue)
 	}
 	if v.Kind() != reflect.Map {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(m, v, ErrKindMismatch)
 	}
 
-	key := reflect.Value(m)
-	if !key.IsValid() {
-		return zeroValue, ErrTodo
+	if !reflect.Value(m).IsValid() {
+		return zeroValue, newPathError(m, v, ErrInvalidValue)
 	}
-	if !key.Type().AssignableTo(v.Type().Key()) {
-		return zeroValue, ErrTodo
+	key, ok := mapKey(reflect.Value(m), v.Type().Key())
+	if !ok {
+		return zeroValue, newPathError(m, v, ErrKindMismatch)
 	}
 
 	if v.IsNil() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(m, v, ErrKeyNotFound)
 	}
 	found := v.MapIndex(key)
 	if !found.IsValid() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(m, v, ErrKeyNotFound)
 	}
 
 	return key, nil
@@ -230,10 +914,49 @@ func (m MapKeyPart) elems() iter.Seq[Path] {
 	}
 }
 
+func (m MapKeyPart) format(f *formatter) error {
+	key, err := formatKey(reflect.Value(m))
+	if err != nil {
+		return err
+	}
+	f.postfix("{" + key + "}")
+	return nil
+}
+
+func (m MapKeyPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	if _, err := m.Traverse(v); err != nil {
+		return slot{}, err
+	}
+	return slot{}, newPathError(m, v, ErrNotAddressable)
+}
+
+func (m MapKeyPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(m, t, ErrInvalidValue)
+	}
+	if t.Kind() != reflect.Map {
+		return nil, newTypeError(m, t, ErrKindMismatch)
+	}
+	if !reflect.Value(m).IsValid() {
+		return nil, newTypeError(m, t, ErrInvalidValue)
+	}
+	key, ok := mapKey(reflect.Value(m), t.Key())
+	if !ok {
+		return nil, newTypeError(m, t, ErrKindMismatch)
+	}
+	return key.Type(), nil
+}
+
 func MapValueOfKey[K comparable](k K) Path {
 	return MapValueOfKeyPart(reflect.ValueOf(k))
 }
 
+// MapValueOfKeyOf is like MapValueOfKey, but takes a key that is already a reflect.Value.
+// A key held in an interface is unwrapped.
+func MapValueOfKeyOf(k reflect.Value) Path {
+	return MapValueOfKeyPart(unwrapKey(k))
+}
+
 type MapValueOfKeyPart reflect.Value
 
 func (m MapValueOfKeyPart) String() string {
@@ -242,26 +965,26 @@ func (m MapValueOfKeyPart) String() string {
 
 func (m MapValueOfKeyPart) Traverse(v reflect.Value) (reflect.Value, error) {
 	if !v.IsValid() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(m, v, ErrInvalidValue)
 	}
 	if v.Kind() != reflect.Map {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(m, v, ErrKindMismatch)
 	}
 
-	key := reflect.Value(m)
-	if !key.IsValid() {
-		return zeroValue, ErrTodo
+	if !reflect.Value(m).IsValid() {
+		return zeroValue, newPathError(m, v, ErrInvalidValue)
 	}
-	if !key.Type().AssignableTo(v.Type().Key()) {
-		return zeroValue, ErrTodo
+	key, ok := mapKey(reflect.Value(m), v.Type().Key())
+	if !ok {
+		return zeroValue, newPathError(m, v, ErrKindMismatch)
 	}
 
 	if v.IsNil() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(m, v, ErrKeyNotFound)
 	}
 	val := v.MapIndex(key)
 	if !val.IsValid() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(m, v, ErrKeyNotFound)
 	}
 
 	return val, nil
@@ -273,6 +996,92 @@ func (m MapValueOfKeyPart) elems() iter.Seq[Path] {
 	}
 }
 
+func (m MapValueOfKeyPart) format(f *formatter) error {
+	key, err := formatKey(reflect.Value(m))
+	if err != nil {
+		return err
+	}
+	f.postfix("[" + key + "]")
+	return nil
+}
+
+func (m MapValueOfKeyPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	if !v.IsValid() {
+		return slot{}, newPathError(m, v, ErrInvalidValue)
+	}
+	if v.Kind() != reflect.Map {
+		return slot{}, newPathError(m, v, ErrKindMismatch)
+	}
+
+	if !reflect.Value(m).IsValid() {
+		return slot{}, newPathError(m, v, ErrInvalidValue)
+	}
+	key, ok := mapKey(reflect.Value(m), v.Type().Key())
+	if !ok {
+		return slot{}, newPathError(m, v, ErrKindMismatch)
+	}
+
+	vivified := false
+	if v.IsNil() {
+		if !opts.Vivify || !v.CanSet() {
+			return slot{}, newPathError(m, v, ErrNilPointer)
+		}
+		v.Set(reflect.MakeMap(v.Type()))
+		vivified = true
+	}
+	// Map values are not addressable, so modifications are made to a copy which is then
+	// stored back into the map.  Storing a key that isn't present yet adds a new entry.
+	copied := reflect.New(v.Type().Elem()).Elem()
+	if found := v.MapIndex(key); found.IsValid() {
+		copied.Set(found)
+	}
+	store := func() error {
+		v.SetMapIndex(key, copied)
+		return nil
+	}
+	return slot{v: copied, store: store, indirect: true, vivified: vivified}, nil
+}
+
+func (m MapValueOfKeyPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(m, t, ErrInvalidValue)
+	}
+	if t.Kind() != reflect.Map {
+		return nil, newTypeError(m, t, ErrKindMismatch)
+	}
+	if !reflect.Value(m).IsValid() {
+		return nil, newTypeError(m, t, ErrInvalidValue)
+	}
+	if _, ok := mapKey(reflect.Value(m), t.Key()); !ok {
+		return nil, newTypeError(m, t, ErrKindMismatch)
+	}
+	return t.Elem(), nil
+}
+
+func unwrapKey(k reflect.Value) reflect.Value {
+	if k.Kind() == reflect.Interface && !k.IsNil() {
+		return k.Elem()
+	}
+	return k
+}
+
+// mapKey converts key for use with maps whose keys have type t.  Keys that aren't assignable
+// to t are converted if no information is lost, so that for example an int can be used with
+// a map[int64]T, and a string with a map keyed by a named string type.
+func mapKey(key reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if key.Type().AssignableTo(t) {
+		return key, true
+	}
+	if !convertible(key.Type(), t) || !key.Comparable() {
+		return zeroValue, false
+	}
+	converted := key.Convert(t)
+	if !convertible(t, key.Type()) || !converted.Convert(key.Type()).Equal(key) {
+		return zeroValue, false
+	}
+	return converted, true
+}
+
 func ExportedField(name string) Path {
 	return ExportedFieldPart(name)
 }
@@ -283,29 +1092,29 @@ func (f ExportedFieldPart) String() string {
 	return fmt.Sprintf("<exported field %s>", string(f))
 }
 
-// TODO: currently this supports finding promoted fields from embedded structs.  It isn't clear to me that
-// this is actually something we want to support.  It would mean that there is more than one way to
-// address a field, which seems like it could lead to confusion.
+// This supports finding promoted fields from embedded structs, which means that there is more than one
+// way to address a field.  Canonicalize rewrites paths into a single form, and FieldIndex can be used to
+// address a field unambiguously.
 func (f ExportedFieldPart) Traverse(v reflect.Value) (reflect.Value, error) {
 	if !v.IsValid() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(f, v, ErrInvalidValue)
 	}
 	if v.Kind() != reflect.Struct {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(f, v, ErrKindMismatch)
 	}
 	t := v.Type()
 	fieldDesc, ok := t.FieldByName(string(f))
 	if !ok {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(f, v, ErrNoSuchField)
 	}
 	if !fieldDesc.IsExported() {
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(f, v, ErrNoSuchField)
 	}
 
 	fieldValue, err := v.FieldByIndexErr(fieldDesc.Index)
 	if err != nil {
 		// This happens if the field requires traversing a nil pointer.
-		return zeroValue, ErrTodo
+		return zeroValue, newPathError(f, v, ErrNilPointer)
 	}
 	return fieldValue, nil
 }
@@ -315,3 +1124,445 @@ func (f ExportedFieldPart) elems() iter.Seq[Path] {
 		yield(f)
 	}
 }
+
+func (f ExportedFieldPart) format(fm *formatter) error {
+	if fm.tagKey != "" {
+		return fmt.Errorf("field names refer to %s tags", fm.tagKey)
+	}
+	name, err := formatIdent(string(f))
+	if err != nil {
+		return err
+	}
+	fm.selector(name)
+	return nil
+}
+
+func (f ExportedFieldPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	if _, err := f.Traverse(v); err != nil && !(opts.Vivify && errors.Is(err, ErrNilPointer)) {
+		return slot{}, err
+	}
+	fieldDesc, _ := v.Type().FieldByName(string(f))
+	return editField(f, v, fieldDesc.Index, opts)
+}
+
+func (f ExportedFieldPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(f, t, ErrInvalidValue)
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, newTypeError(f, t, ErrKindMismatch)
+	}
+	fieldDesc, ok := t.FieldByName(string(f))
+	if !ok || !fieldDesc.IsExported() {
+		return nil, newTypeError(f, t, ErrNoSuchField)
+	}
+	return fieldDesc.Type, nil
+}
+
+// editField implements edit for steps that address the field of v at index, as used by
+// reflect.Value.FieldByIndex.  When opts.AllowUnexported is set, unexported fields along the
+// way are made settable.
+func editField(step Path, v reflect.Value, index []int, opts *SetOptions) (slot, error) {
+	// Promoted fields may be reached through embedded pointers, in which case they are not
+	// part of v's own memory.
+	field := v
+	indirect := false
+	vivified := false
+	for n, i := range index {
+		field = field.Field(i)
+		if opts.AllowUnexported && field.CanAddr() {
+			field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+		}
+		if n == len(index)-1 || field.Kind() != reflect.Pointer {
+			continue
+		}
+		if field.IsNil() {
+			if !opts.Vivify || !field.CanSet() {
+				return slot{}, newPathError(step, v, ErrNilPointer)
+			}
+			field.Set(reflect.New(field.Type().Elem()))
+			vivified = vivified || !indirect
+		} else if opts.copyOnWrite {
+			if _, err := unshare(field); err != nil {
+				return slot{}, newPathError(step, v, err)
+			}
+			vivified = vivified || !indirect
+		}
+		field = field.Elem()
+		indirect = true
+	}
+	return slot{v: field, indirect: indirect, vivified: vivified}, nil
+}
+
+func Method(name string) Path {
+	return MethodPart(name)
+}
+
+// MethodPart calls an exported getter method: one that takes no arguments and returns either a
+// single value, or a value and an error.  Methods with pointer receivers are found when the
+// current value is addressable.
+type MethodPart string
+
+func (m MethodPart) String() string {
+	return fmt.Sprintf("<method %s>", string(m))
+}
+
+func (m MethodPart) Traverse(v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() {
+		return zeroValue, newPathError(m, v, ErrInvalidValue)
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return zeroValue, newPathError(m, v, ErrNilPointer)
+		}
+	}
+
+	method := v.MethodByName(string(m))
+	if !method.IsValid() && v.Kind() != reflect.Interface && v.CanAddr() {
+		method = v.Addr().MethodByName(string(m))
+	}
+	if !method.IsValid() {
+		return zeroValue, newPathError(m, v, ErrNoSuchMethod)
+	}
+	if !isGetter(method.Type()) {
+		return zeroValue, newPathError(m, v, fmt.Errorf("%w: %s has signature %s", ErrNoSuchMethod, string(m), method.Type()))
+	}
+
+	out := method.Call(nil)
+	if len(out) == 2 && !out[1].IsNil() {
+		return zeroValue, newPathError(m, v, out[1].Interface().(error))
+	}
+	return out[0], nil
+}
+
+func (m MethodPart) elems() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		yield(m)
+	}
+}
+
+func (m MethodPart) format(f *formatter) error {
+	name, err := formatIdent(string(m))
+	if err != nil {
+		return err
+	}
+	f.selector(name + "()")
+	return nil
+}
+
+func (m MethodPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	result, err := m.Traverse(v)
+	if err != nil {
+		return slot{}, err
+	}
+	// Results are never addressable, but if the method returns a pointer then the value it
+	// points to may be modified.
+	return slot{v: result, indirect: true}, nil
+}
+
+func (m MethodPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(m, t, ErrInvalidValue)
+	}
+	method, ok := t.MethodByName(string(m))
+	if !ok && t.Kind() != reflect.Interface && t.Kind() != reflect.Pointer {
+		// Whether pointer methods can be called depends on whether the value is addressable,
+		// which isn't known from the type alone.
+		method, ok = reflect.PointerTo(t).MethodByName(string(m))
+	}
+	if !ok {
+		return nil, newTypeError(m, t, ErrNoSuchMethod)
+	}
+	methodType := method.Type
+	if t.Kind() != reflect.Interface {
+		// Drop the receiver, to match the type of a method value.
+		in := make([]reflect.Type, methodType.NumIn()-1)
+		for i := range in {
+			in[i] = methodType.In(i + 1)
+		}
+		out := make([]reflect.Type, methodType.NumOut())
+		for i := range out {
+			out[i] = methodType.Out(i)
+		}
+		methodType = reflect.FuncOf(in, out, methodType.IsVariadic())
+	}
+	if !isGetter(methodType) {
+		return nil, newTypeError(m, t, fmt.Errorf("%w: %s has signature %s", ErrNoSuchMethod, string(m), methodType))
+	}
+	return methodType.Out(0), nil
+}
+
+var errorType = reflect.TypeFor[error]()
+
+// isGetter reports whether a method of type t, with its receiver already bound, takes no
+// arguments and returns either a single value, or a value and an error.  Methods that only
+// return an error, like Close or Flush, are called for their effects and aren't getters.
+func isGetter(t reflect.Type) bool {
+	if t.NumIn() != 0 {
+		return false
+	}
+	switch t.NumOut() {
+	case 1:
+		return t.Out(0) != errorType
+	case 2:
+		return t.Out(1) == errorType
+	default:
+		return false
+	}
+}
+
+func UnexportedField(name string) Path {
+	return UnexportedFieldPart(name)
+}
+
+// UnexportedFieldPart reads an unexported field.  Traverse returns an unaddressable copy of the
+// field, so the field itself can't be set through the result, and Set refuses to modify it
+// unless SetOptions.AllowUnexported is set.  Later steps of the same path also yield copies, as
+// described by ReadOnly.  Pointers, slices and maps in the copy still refer to the original
+// memory, so values reached from the result with reflect directly, rather than by path steps,
+// may be modifiable.
+type UnexportedFieldPart string
+
+func (f UnexportedFieldPart) String() string {
+	return fmt.Sprintf("<unexported field %s>", string(f))
+}
+
+func (f UnexportedFieldPart) Traverse(v reflect.Value) (reflect.Value, error) {
+	fieldDesc, err := f.lookup(v)
+	if err != nil {
+		return zeroValue, err
+	}
+	if !v.CanAddr() {
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		v = copied
+	}
+	fieldValue, err := v.FieldByIndexErr(fieldDesc.Index)
+	if err != nil {
+		return zeroValue, newPathError(f, v, ErrNilPointer)
+	}
+	fieldValue = reflect.NewAt(fieldValue.Type(), unsafe.Pointer(fieldValue.UnsafeAddr())).Elem()
+	return readOnly(fieldValue), nil
+}
+
+// ReadOnly reports whether p passes through an UnexportedField step.  Traversing such a path
+// yields unaddressable copies, even where later steps follow pointers or index slices that
+// would otherwise lead back to settable memory.  Only the result itself is copied: like the
+// result of UnexportedField, it may hold pointers, slices or maps into the original memory.
+func ReadOnly(p Path) bool {
+	for step := range p.elems() {
+		if reachesUnexported(step) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadOnlyAfter returns v, which was reached by following p, as traversing p would return it:
+// an unaddressable copy if p passes through an UnexportedField step, and v itself otherwise.
+func ReadOnlyAfter(p Path, v reflect.Value) reflect.Value {
+	if ReadOnly(p) {
+		return readOnly(v)
+	}
+	return v
+}
+
+func reachesUnexported(step Path) bool {
+	_, ok := step.(UnexportedFieldPart)
+	return ok
+}
+
+// readOnlyTracker follows the steps of a path as they are taken, so that every value after an
+// UnexportedField step is made read-only.
+type readOnlyTracker struct {
+	hidden bool
+}
+
+// step records that step was taken, and reports whether its result must be made read-only.
+func (r *readOnlyTracker) step(step Path) bool {
+	ro := r.hidden
+	r.hidden = r.hidden || reachesUnexported(step)
+	return ro
+}
+
+// next records that step was taken and returns v, its result, made read-only if needed.
+func (r *readOnlyTracker) next(step Path, v reflect.Value) reflect.Value {
+	if r.step(step) {
+		return readOnly(v)
+	}
+	return v
+}
+
+// readOnly returns an unaddressable copy of v if v is addressable.
+func readOnly(v reflect.Value) reflect.Value {
+	if !v.CanAddr() {
+		return v
+	}
+	// Converting to the same type makes an unaddressable copy.
+	return v.Convert(v.Type())
+}
+
+func (f UnexportedFieldPart) lookup(v reflect.Value) (reflect.StructField, error) {
+	if !v.IsValid() {
+		return reflect.StructField{}, newPathError(f, v, ErrInvalidValue)
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.StructField{}, newPathError(f, v, ErrKindMismatch)
+	}
+	fieldDesc, ok := v.Type().FieldByName(string(f))
+	if !ok || fieldDesc.IsExported() {
+		return reflect.StructField{}, newPathError(f, v, ErrNoSuchField)
+	}
+	return fieldDesc, nil
+}
+
+func (f UnexportedFieldPart) elems() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		yield(f)
+	}
+}
+
+func (f UnexportedFieldPart) format(fm *formatter) error {
+	name, err := formatIdent(string(f))
+	if err != nil {
+		return err
+	}
+	fm.selector("~" + name)
+	return nil
+}
+
+func (f UnexportedFieldPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	fieldDesc, err := f.lookup(v)
+	if err != nil {
+		return slot{}, err
+	}
+	if !opts.AllowUnexported {
+		return slot{}, newPathError(f, v, ErrUnexported)
+	}
+	if !v.CanAddr() {
+		return slot{}, newPathError(f, v, ErrNotAddressable)
+	}
+	return editField(f, v, fieldDesc.Index, opts)
+}
+
+func (f UnexportedFieldPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(f, t, ErrInvalidValue)
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, newTypeError(f, t, ErrKindMismatch)
+	}
+	fieldDesc, ok := t.FieldByName(string(f))
+	if !ok || fieldDesc.IsExported() {
+		return nil, newTypeError(f, t, ErrNoSuchField)
+	}
+	return fieldDesc.Type, nil
+}
+
+func FieldIndex(index []int) Path {
+	return FieldIndexPart(slices.Clone(index))
+}
+
+// FieldIndexPart addresses an exported struct field by its index sequence, as in
+// reflect.StructField.Index.  Unlike ExportedFieldPart, which may find a promoted field by
+// name, it identifies exactly one field.
+type FieldIndexPart []int
+
+func (f FieldIndexPart) String() string {
+	return fmt.Sprintf("<field index %v>", []int(f))
+}
+
+func (f FieldIndexPart) Traverse(v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() {
+		return zeroValue, newPathError(f, v, ErrInvalidValue)
+	}
+	if v.Kind() != reflect.Struct {
+		return zeroValue, newPathError(f, v, ErrKindMismatch)
+	}
+	if _, err := lookupFieldIndex(v.Type(), f); err != nil {
+		return zeroValue, newPathError(f, v, err)
+	}
+	fieldValue, err := v.FieldByIndexErr(f)
+	if err != nil {
+		return zeroValue, newPathError(f, v, ErrNilPointer)
+	}
+	return fieldValue, nil
+}
+
+func (f FieldIndexPart) elems() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		yield(f)
+	}
+}
+
+func (f FieldIndexPart) format(fm *formatter) error {
+	if len(f) == 0 {
+		return errors.New("empty field index")
+	}
+	b := &strings.Builder{}
+	b.WriteString("#")
+	for n, i := range f {
+		if i < 0 {
+			return fmt.Errorf("negative field index %d", i)
+		}
+		if n > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(strconv.Itoa(i))
+	}
+	fm.selector(b.String())
+	return nil
+}
+
+func (f FieldIndexPart) edit(v reflect.Value, opts *SetOptions) (slot, error) {
+	if _, err := f.Traverse(v); err != nil && !(opts.Vivify && errors.Is(err, ErrNilPointer)) {
+		return slot{}, err
+	}
+	return editField(f, v, f, opts)
+}
+
+func (f FieldIndexPart) resolveType(t reflect.Type) (reflect.Type, error) {
+	if t == nil {
+		return nil, newTypeError(f, t, ErrInvalidValue)
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, newTypeError(f, t, ErrKindMismatch)
+	}
+	fieldDesc, err := lookupFieldIndex(t, f)
+	if err != nil {
+		return nil, newTypeError(f, t, err)
+	}
+	return fieldDesc.Type, nil
+}
+
+// lookupFieldIndex finds the field of struct type t at index, stepping through pointers the
+// same way as reflect.Value.FieldByIndex.  It fails if the field doesn't exist or isn't
+// accessible, either because it is unexported or because it is reached through an unexported
+// field that isn't embedded.
+func lookupFieldIndex(t reflect.Type, index []int) (reflect.StructField, error) {
+	if len(index) == 0 {
+		return reflect.StructField{}, ErrNoSuchField
+	}
+	var fieldDesc reflect.StructField
+	for n, i := range index {
+		if n > 0 {
+			if !fieldDesc.IsExported() && !fieldDesc.Anonymous {
+				return reflect.StructField{}, ErrNoSuchField
+			}
+			if t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+		}
+		if t.Kind() != reflect.Struct || i < 0 || i >= t.NumField() {
+			return reflect.StructField{}, ErrNoSuchField
+		}
+		fieldDesc = t.Field(i)
+		t = fieldDesc.Type
+	}
+	if !fieldDesc.IsExported() {
+		return reflect.StructField{}, ErrNoSuchField
+	}
+	fieldDesc.Index = slices.Clone(index)
+	return fieldDesc, nil
+}
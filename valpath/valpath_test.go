@@ -2,8 +2,10 @@ package valpath_test
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/krelinga/go-reflection-playground/testtypes"
 	"github.com/krelinga/go-reflection-playground/valpath"
@@ -33,32 +35,32 @@ func TestValPath(t *testing.T) {
 				{
 					name:    "deref",
 					path:    valpath.Deref(),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "interface",
 					path:    valpath.Inter(),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "index",
 					path:    valpath.Index(0),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "map key",
 					path:    valpath.MapKey("key"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "map value",
 					path:    valpath.MapValueOfKey("key"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "exported field",
 					path:    valpath.ExportedField("Int"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 			},
 		},
@@ -74,7 +76,7 @@ func TestValPath(t *testing.T) {
 				{
 					name:    "deref",
 					path:    valpath.Deref(),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "interface",
@@ -84,22 +86,22 @@ func TestValPath(t *testing.T) {
 				{
 					name:    "index",
 					path:    valpath.Index(0),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "map key",
 					path:    valpath.MapKey("key"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "map value",
 					path:    valpath.MapValueOfKey("key"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "exported field",
 					path:    valpath.ExportedField("Int"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 			},
 		},
@@ -115,12 +117,12 @@ func TestValPath(t *testing.T) {
 				{
 					name:    "deref",
 					path:    valpath.Deref(),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "interface",
 					path:    valpath.Inter(),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "index",
@@ -130,17 +132,17 @@ func TestValPath(t *testing.T) {
 				{
 					name:    "map key",
 					path:    valpath.MapKey("key"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "map value",
 					path:    valpath.MapValueOfKey("key"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "exported field",
 					path:    valpath.ExportedField("Int"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 			},
 		},
@@ -156,17 +158,17 @@ func TestValPath(t *testing.T) {
 				{
 					name:    "deref",
 					path:    valpath.Deref(),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "interface",
 					path:    valpath.Inter(),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "index",
 					path:    valpath.Index(0),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "map key",
@@ -181,7 +183,7 @@ func TestValPath(t *testing.T) {
 				{
 					name:    "exported field",
 					path:    valpath.ExportedField("Int"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 			},
 		},
@@ -197,27 +199,27 @@ func TestValPath(t *testing.T) {
 				{
 					name:    "deref",
 					path:    valpath.Deref(),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "interface",
 					path:    valpath.Inter(),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "index",
 					path:    valpath.Index(0),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "map key",
 					path:    valpath.MapKey("key"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "map value",
 					path:    valpath.MapValueOfKey("key"),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrKindMismatch,
 				},
 				{
 					name:    "exported field",
@@ -271,7 +273,7 @@ func TestValPath(t *testing.T) {
 				{
 					name:    "access promoted field",
 					path:    valpath.Join(valpath.ExportedField("Int")),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrNilPointer,
 				},
 				{
 					name: "access non-promoted field",
@@ -279,7 +281,7 @@ func TestValPath(t *testing.T) {
 						valpath.ExportedField("Inner"),
 						valpath.Deref(),
 						valpath.ExportedField("Int")),
-					wantErr: valpath.ErrTodo,
+					wantErr: valpath.ErrNilPointer,
 				},
 			},
 		},
@@ -336,3 +338,979 @@ func TestValPath(t *testing.T) {
 		t.Fatalf("expected dereferenced value to be 42, got %v", result)
 	}
 }
+
+type getters struct {
+	n   int
+	ptr *int
+}
+
+func (g getters) Value() int {
+	return g.n
+}
+
+func (g getters) Checked() (int, error) {
+	if g.n < 0 {
+		return 0, errNegative
+	}
+	return g.n, nil
+}
+
+func (g getters) Ptr() *int {
+	return g.ptr
+}
+
+func (g getters) Plus(i int) int {
+	return g.n + i
+}
+
+func (g getters) Pair() (int, int) {
+	return g.n, g.n
+}
+
+func (g *getters) PtrValue() int {
+	return g.n
+}
+
+var errNegative = errors.New("negative")
+
+func TestMethod(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      reflect.Value
+		path    valpath.Path
+		wantAny any
+		wantErr error
+	}{
+		{
+			name:    "value receiver",
+			in:      reflect.ValueOf(getters{n: 42}),
+			path:    valpath.Method("Value"),
+			wantAny: 42,
+		},
+		{
+			name:    "value and nil error",
+			in:      reflect.ValueOf(getters{n: 42}),
+			path:    valpath.Method("Checked"),
+			wantAny: 42,
+		},
+		{
+			name:    "method error",
+			in:      reflect.ValueOf(getters{n: -1}),
+			path:    valpath.Method("Checked"),
+			wantErr: errNegative,
+		},
+		{
+			name:    "pointer receiver on addressable value",
+			in:      reflect.ValueOf(&getters{n: 42}).Elem(),
+			path:    valpath.Method("PtrValue"),
+			wantAny: 42,
+		},
+		{
+			name:    "pointer receiver on unaddressable value",
+			in:      reflect.ValueOf(getters{n: 42}),
+			path:    valpath.Method("PtrValue"),
+			wantErr: valpath.ErrNoSuchMethod,
+		},
+		{
+			name:    "pointer receiver through pointer",
+			in:      reflect.ValueOf(&getters{n: 42}),
+			path:    valpath.Method("PtrValue"),
+			wantAny: 42,
+		},
+		{
+			name:    "method takes arguments",
+			in:      reflect.ValueOf(getters{n: 42}),
+			path:    valpath.Method("Plus"),
+			wantErr: valpath.ErrNoSuchMethod,
+		},
+		{
+			name:    "second result is not an error",
+			in:      reflect.ValueOf(getters{n: 42}),
+			path:    valpath.Method("Pair"),
+			wantErr: valpath.ErrNoSuchMethod,
+		},
+		{
+			name:    "missing method",
+			in:      reflect.ValueOf(getters{n: 42}),
+			path:    valpath.Method("hidden"),
+			wantErr: valpath.ErrNoSuchMethod,
+		},
+		{
+			name:    "nil pointer",
+			in:      reflect.ValueOf((*getters)(nil)),
+			path:    valpath.Method("Value"),
+			wantErr: valpath.ErrNilPointer,
+		},
+		{
+			name:    "interface value",
+			in:      testtypes.NewIFaceValue(42),
+			path:    valpath.Method("String"),
+			wantAny: "42",
+		},
+		{
+			name:    "continue through result",
+			in:      reflect.ValueOf(getters{ptr: new(int)}),
+			path:    valpath.Join(valpath.Method("Ptr"), valpath.Deref()),
+			wantAny: 0,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.path.Traverse(tt.in)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.wantAny) {
+				t.Errorf("got value %v, want %v", got.Interface(), tt.wantAny)
+			}
+		})
+	}
+
+	t.Run("set through pointer result", func(t *testing.T) {
+		i := 0
+		g := getters{ptr: &i}
+		if err := valpath.Set(reflect.ValueOf(g), valpath.Join(valpath.Method("Ptr"), valpath.Deref()), reflect.ValueOf(42)); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if i != 42 {
+			t.Errorf("got %d, want 42", i)
+		}
+		err := valpath.Set(reflect.ValueOf(&g).Elem(), valpath.Method("Value"), reflect.ValueOf(42))
+		if !errors.Is(err, valpath.ErrNotAddressable) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNotAddressable)
+		}
+	})
+}
+
+type embedded struct {
+	depth int
+}
+
+type private struct {
+	embedded
+	count int
+	inner *testtypes.Inner
+	items []int
+	Int   int
+}
+
+func TestUnexportedField(t *testing.T) {
+	newPrivate := func() private {
+		return private{embedded: embedded{depth: 1}, count: 2, inner: &testtypes.Inner{Int: 3}, items: []int{5}, Int: 4}
+	}
+	testCases := []struct {
+		name    string
+		path    valpath.Path
+		wantAny any
+		wantErr error
+	}{
+		{
+			name:    "unexported field",
+			path:    valpath.UnexportedField("count"),
+			wantAny: 2,
+		},
+		{
+			name:    "promoted unexported field",
+			path:    valpath.UnexportedField("depth"),
+			wantAny: 1,
+		},
+		{
+			name:    "through unexported pointer",
+			path:    valpath.Join(valpath.UnexportedField("inner"), valpath.Deref(), valpath.ExportedField("Int")),
+			wantAny: 3,
+		},
+		{
+			name:    "exported field",
+			path:    valpath.UnexportedField("Int"),
+			wantErr: valpath.ErrNoSuchField,
+		},
+		{
+			name:    "exported access to unexported field",
+			path:    valpath.ExportedField("count"),
+			wantErr: valpath.ErrNoSuchField,
+		},
+		{
+			name:    "missing field",
+			path:    valpath.UnexportedField("missing"),
+			wantErr: valpath.ErrNoSuchField,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, in := range []reflect.Value{
+				reflect.ValueOf(newPrivate()),
+				reflect.ValueOf(ptrTo(newPrivate())).Elem(),
+			} {
+				got, err := tt.path.Traverse(in)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("got error %v, want %v", err, tt.wantErr)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("got error %v, want no error", err)
+				}
+				if !reflect.DeepEqual(got.Interface(), tt.wantAny) {
+					t.Errorf("got value %v, want %v", got.Interface(), tt.wantAny)
+				}
+			}
+		})
+	}
+
+	t.Run("result is read-only", func(t *testing.T) {
+		got, err := valpath.UnexportedField("count").Traverse(reflect.ValueOf(ptrTo(newPrivate())).Elem())
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got.CanSet() {
+			t.Error("got settable value, want read-only value")
+		}
+	})
+
+	t.Run("results through slices are read-only", func(t *testing.T) {
+		p := newPrivate()
+		got, err := valpath.Join(valpath.UnexportedField("items"), valpath.Index(0)).Traverse(reflect.ValueOf(&p).Elem())
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got.CanSet() {
+			t.Error("got settable value, want read-only value")
+		}
+	})
+
+	// Only the field's own copy is protected.  Pointers, slices and maps within it still lead
+	// to the original memory when followed with reflect directly.
+	t.Run("referenced values are shared", func(t *testing.T) {
+		p := newPrivate()
+		root := reflect.ValueOf(&p).Elem()
+		items, err := valpath.UnexportedField("items").Traverse(root)
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		inner, err := valpath.UnexportedField("inner").Traverse(root)
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if items.CanSet() || inner.CanSet() {
+			t.Error("got settable field, want read-only field")
+		}
+		items.Index(0).SetInt(99)
+		inner.Elem().Field(0).SetInt(42)
+		if p.items[0] != 99 || p.inner.Int != 42 {
+			t.Errorf("got items %v and inner %v, want them shared with the results", p.items, *p.inner)
+		}
+	})
+
+	t.Run("results through pointers are read-only", func(t *testing.T) {
+		p := newPrivate()
+		root := reflect.ValueOf(&p).Elem()
+		path := valpath.Join(valpath.UnexportedField("inner"), valpath.Deref(), valpath.ExportedField("Int"))
+		accessor, err := valpath.Compile(root.Type(), path)
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		traced := valpath.Trace(root, path)
+		results := map[string]func() (reflect.Value, error){
+			"Traverse": func() (reflect.Value, error) { return path.Traverse(root) },
+			"TraverseWith": func() (reflect.Value, error) {
+				got, _, err := valpath.TraverseWith(root, path, valpath.Options{AutoDeref: true})
+				return got, err
+			},
+			"Trace":    func() (reflect.Value, error) { return traced[len(traced)-1].Out, traced[len(traced)-1].Err },
+			"Accessor": func() (reflect.Value, error) { return accessor.Get(root) },
+		}
+		for name, result := range results {
+			got, err := result()
+			if err != nil {
+				t.Fatalf("%s: got error %v, want no error", name, err)
+			}
+			if got.CanSet() {
+				t.Errorf("%s: got settable value, want read-only value", name)
+			}
+			if got.Interface() != 3 {
+				t.Errorf("%s: got %v, want 3", name, got.Interface())
+			}
+		}
+		if !valpath.ReadOnly(path) || valpath.ReadOnly(valpath.ExportedField("Int")) {
+			t.Error("ReadOnly does not match the paths that pass through unexported fields")
+		}
+		if _, err := valpath.PointerTo(root, path); !errors.Is(err, valpath.ErrNotAddressable) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNotAddressable)
+		}
+	})
+
+	t.Run("set refused by default", func(t *testing.T) {
+		p := newPrivate()
+		err := valpath.SetAt(&p, valpath.UnexportedField("count"), 42)
+		if !errors.Is(err, valpath.ErrUnexported) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrUnexported)
+		}
+		err = valpath.SetAt(&p, valpath.Join(valpath.UnexportedField("inner"), valpath.Deref(), valpath.ExportedField("Int")), 42)
+		if !errors.Is(err, valpath.ErrUnexported) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrUnexported)
+		}
+		if !reflect.DeepEqual(p, newPrivate()) {
+			t.Errorf("got %+v, want unmodified value", p)
+		}
+	})
+
+	t.Run("set when allowed", func(t *testing.T) {
+		opts := valpath.SetOptions{AllowUnexported: true}
+		p := newPrivate()
+		root := reflect.ValueOf(&p).Elem()
+		for _, path := range []valpath.Path{
+			valpath.UnexportedField("count"),
+			valpath.UnexportedField("depth"),
+			valpath.Join(valpath.UnexportedField("inner"), valpath.Deref(), valpath.ExportedField("Int")),
+		} {
+			if err := valpath.SetWith(root, path, reflect.ValueOf(42), opts); err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+		}
+		want := private{embedded: embedded{depth: 42}, count: 42, inner: &testtypes.Inner{Int: 42}, items: []int{5}, Int: 4}
+		if !reflect.DeepEqual(p, want) {
+			t.Errorf("got %+v, want %+v", p, want)
+		}
+
+		err := valpath.SetWith(reflect.ValueOf(newPrivate()), valpath.UnexportedField("count"), reflect.ValueOf(42), opts)
+		if !errors.Is(err, valpath.ErrNotAddressable) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNotAddressable)
+		}
+	})
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
+func TestFieldIndex(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      any
+		index   []int
+		wantAny any
+		wantErr error
+	}{
+		{
+			name:    "top-level field",
+			in:      testtypes.Inner{Int: 42},
+			index:   []int{0},
+			wantAny: 42,
+		},
+		{
+			name:    "through embedded pointer",
+			in:      testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 42}},
+			index:   []int{0, 0},
+			wantAny: 42,
+		},
+		{
+			name:    "through nil embedded pointer",
+			in:      testtypes.OuterPtr{},
+			index:   []int{0, 0},
+			wantErr: valpath.ErrNilPointer,
+		},
+		{
+			name:    "through unexported embedded struct",
+			in:      viaLower{lowerEmbed: lowerEmbed{Exported: 42}},
+			index:   []int{0, 0},
+			wantAny: 42,
+		},
+		{
+			name:    "unexported field",
+			in:      viaLower{},
+			index:   []int{0, 1},
+			wantErr: valpath.ErrNoSuchField,
+		},
+		{
+			name:    "out of range",
+			in:      testtypes.Inner{},
+			index:   []int{1},
+			wantErr: valpath.ErrNoSuchField,
+		},
+		{
+			name:    "too deep",
+			in:      testtypes.Inner{},
+			index:   []int{0, 0},
+			wantErr: valpath.ErrNoSuchField,
+		},
+		{
+			name:    "empty",
+			in:      testtypes.Inner{},
+			index:   nil,
+			wantErr: valpath.ErrNoSuchField,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valpath.FieldIndex(tt.index).Traverse(reflect.ValueOf(tt.in))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.wantAny) {
+				t.Errorf("got value %v, want %v", got.Interface(), tt.wantAny)
+			}
+		})
+	}
+}
+
+type namedKey string
+
+func TestMapKeyConversion(t *testing.T) {
+	int64Map := map[int64]string{7: "seven"}
+	namedMap := map[namedKey]int{"a": 1}
+	anyMap := map[any]string{3: "three", "s": "ess", nil: "nil"}
+	int8Map := map[int8]string{7: "seven"}
+	testCases := []struct {
+		name    string
+		in      any
+		path    valpath.Path
+		wantAny any
+		wantErr error
+	}{
+		{
+			name:    "untyped int against int64 keys",
+			in:      int64Map,
+			path:    valpath.MapValueOfKey(7),
+			wantAny: "seven",
+		},
+		{
+			name:    "map key is converted",
+			in:      int64Map,
+			path:    valpath.MapKey(7),
+			wantAny: int64(7),
+		},
+		{
+			name:    "string against named string keys",
+			in:      namedMap,
+			path:    valpath.MapValueOfKey("a"),
+			wantAny: 1,
+		},
+		{
+			name:    "reflect.Value key",
+			in:      int64Map,
+			path:    valpath.MapValueOfKeyOf(reflect.ValueOf(int64(7))),
+			wantAny: "seven",
+		},
+		{
+			name:    "key held in interface",
+			in:      int64Map,
+			path:    valpath.MapKeyOf(reflect.ValueOf(&[]any{int64(7)}).Elem().Index(0)),
+			wantAny: int64(7),
+		},
+		{
+			name:    "interface keys",
+			in:      anyMap,
+			path:    valpath.MapValueOfKey(3),
+			wantAny: "three",
+		},
+		{
+			name:    "nil interface key",
+			in:      anyMap,
+			path:    valpath.MapValueOfKeyOf(reflect.ValueOf(&[]any{nil}).Elem().Index(0)),
+			wantAny: "nil",
+		},
+		{
+			name:    "interface key of another type",
+			in:      anyMap,
+			path:    valpath.MapValueOfKey(int64(3)),
+			wantErr: valpath.ErrKeyNotFound,
+		},
+		{
+			name:    "lossy conversion",
+			in:      int8Map,
+			path:    valpath.MapValueOfKey(263),
+			wantErr: valpath.ErrKindMismatch,
+		},
+		{
+			name:    "int against string keys",
+			in:      namedMap,
+			path:    valpath.MapValueOfKey(97),
+			wantErr: valpath.ErrKindMismatch,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			in := reflect.ValueOf(tt.in)
+			got, err := tt.path.Traverse(in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.wantAny) {
+				t.Errorf("got %#v, want %#v", got.Interface(), tt.wantAny)
+			}
+			if _, err := valpath.ResolveType(in.Type(), tt.path); err != nil {
+				t.Errorf("got ResolveType error %v, want no error", err)
+			}
+		})
+	}
+
+	t.Run("set", func(t *testing.T) {
+		m := map[int64]string{}
+		if err := valpath.Set(reflect.ValueOf(m), valpath.MapValueOfKey(7), reflect.ValueOf("seven")); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if m[7] != "seven" {
+			t.Errorf("got %v, want map[7:seven]", m)
+		}
+	})
+
+	t.Run("compile", func(t *testing.T) {
+		a, err := valpath.Compile(reflect.TypeOf(namedMap), valpath.MapValueOfKey("a"))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		got, err := a.Get(reflect.ValueOf(namedMap))
+		if err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got.Interface() != 1 {
+			t.Errorf("got %v, want 1", got)
+		}
+	})
+}
+
+func TestFromEnd(t *testing.T) {
+	items := []int{1, 2, 3}
+	testCases := []struct {
+		name    string
+		in      reflect.Value
+		path    valpath.Path
+		wantAny any
+		wantErr error
+	}{
+		{
+			name:    "last slice element",
+			in:      reflect.ValueOf(items),
+			path:    valpath.FromEnd(1),
+			wantAny: 3,
+		},
+		{
+			name:    "first slice element",
+			in:      reflect.ValueOf(items),
+			path:    valpath.FromEnd(3),
+			wantAny: 1,
+		},
+		{
+			name:    "array element",
+			in:      reflect.ValueOf([2]string{"a", "b"}),
+			path:    valpath.FromEnd(2),
+			wantAny: "a",
+		},
+		{
+			name:    "past the start",
+			in:      reflect.ValueOf(items),
+			path:    valpath.FromEnd(4),
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+		{
+			name:    "zero",
+			in:      reflect.ValueOf(items),
+			path:    valpath.FromEnd(0),
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+		{
+			name:    "empty slice",
+			in:      reflect.ValueOf([]int{}),
+			path:    valpath.FromEnd(1),
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+		{
+			name:    "not a slice",
+			in:      reflect.ValueOf("abc"),
+			path:    valpath.FromEnd(1),
+			wantErr: valpath.ErrKindMismatch,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.path.Traverse(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.Interface() != tt.wantAny {
+				t.Errorf("got %v, want %v", got.Interface(), tt.wantAny)
+			}
+		})
+	}
+
+	t.Run("set", func(t *testing.T) {
+		got := []int{1, 2, 3}
+		if err := valpath.Set(reflect.ValueOf(got), valpath.FromEnd(1), reflect.ValueOf(42)); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if want := []int{1, 2, 42}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func TestSliceRange(t *testing.T) {
+	type named string
+	testCases := []struct {
+		name    string
+		in      reflect.Value
+		path    valpath.Path
+		wantAny any
+		wantErr error
+	}{
+		{
+			name:    "slice",
+			in:      reflect.ValueOf([]int{1, 2, 3, 4}),
+			path:    valpath.SliceRange(1, 3),
+			wantAny: []int{2, 3},
+		},
+		{
+			name:    "empty range",
+			in:      reflect.ValueOf([]int{1, 2}),
+			path:    valpath.SliceRange(2, 2),
+			wantAny: []int{},
+		},
+		{
+			name:    "addressable array",
+			in:      reflect.ValueOf(&[3]int{1, 2, 3}).Elem(),
+			path:    valpath.SliceRange(0, 2),
+			wantAny: []int{1, 2},
+		},
+		{
+			name:    "unaddressable array",
+			in:      reflect.ValueOf([3]int{1, 2, 3}),
+			path:    valpath.SliceRange(0, 2),
+			wantErr: valpath.ErrNotAddressable,
+		},
+		{
+			name:    "string",
+			in:      reflect.ValueOf(named("hello")),
+			path:    valpath.SliceRange(1, 4),
+			wantAny: named("ell"),
+		},
+		{
+			name:    "element of range",
+			in:      reflect.ValueOf([]int{1, 2, 3, 4}),
+			path:    valpath.Join(valpath.SliceRange(1, 3), valpath.FromEnd(1)),
+			wantAny: 3,
+		},
+		{
+			name:    "past the end",
+			in:      reflect.ValueOf([]int{1, 2}),
+			path:    valpath.SliceRange(1, 3),
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+		{
+			name:    "inverted",
+			in:      reflect.ValueOf([]int{1, 2}),
+			path:    valpath.SliceRange(2, 1),
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+		{
+			name:    "not sliceable",
+			in:      reflect.ValueOf(42),
+			path:    valpath.SliceRange(0, 0),
+			wantErr: valpath.ErrKindMismatch,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.path.Traverse(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.wantAny) {
+				t.Errorf("got %#v, want %#v", got.Interface(), tt.wantAny)
+			}
+			gotType, err := valpath.ResolveType(tt.in.Type(), tt.path)
+			if err != nil {
+				t.Fatalf("got ResolveType error %v, want no error", err)
+			}
+			if gotType != got.Type() {
+				t.Errorf("got ResolveType %v, want %v", gotType, got.Type())
+			}
+		})
+	}
+
+	t.Run("set elements", func(t *testing.T) {
+		got := []int{1, 2, 3, 4}
+		if err := valpath.Set(reflect.ValueOf(got), valpath.SliceRange(1, 3), reflect.ValueOf([]int{5, 6})); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if want := []int{1, 5, 6, 4}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("set array elements in map", func(t *testing.T) {
+		got := map[string][3]int{"a": {1, 2, 3}}
+		path := valpath.Join(valpath.MapValueOfKey("a"), valpath.SliceRange(0, 2), valpath.Index(1))
+		if err := valpath.Set(reflect.ValueOf(got), path, reflect.ValueOf(42)); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if want := [3]int{1, 42, 3}; got["a"] != want {
+			t.Errorf("got %v, want %v", got["a"], want)
+		}
+	})
+
+	t.Run("set wrong length", func(t *testing.T) {
+		err := valpath.Set(reflect.ValueOf([]int{1, 2, 3}), valpath.SliceRange(1, 3), reflect.ValueOf([]int{5}))
+		if !errors.Is(err, valpath.ErrNotAssignable) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNotAssignable)
+		}
+	})
+
+	t.Run("set substring", func(t *testing.T) {
+		got := struct{ S string }{S: "hello"}
+		path := valpath.Join(valpath.ExportedField("S"), valpath.SliceRange(1, 4))
+		if err := valpath.Set(reflect.ValueOf(&got).Elem(), path, reflect.ValueOf("ipp")); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got.S != "hippo" {
+			t.Errorf("got %q, want %q", got.S, "hippo")
+		}
+	})
+}
+
+func TestStringIndex(t *testing.T) {
+	type named string
+	testCases := []struct {
+		name    string
+		in      reflect.Value
+		path    valpath.Path
+		wantAny any
+		wantErr error
+	}{
+		{
+			name:    "ascii byte",
+			in:      reflect.ValueOf("hello"),
+			path:    valpath.ByteAt(1),
+			wantAny: byte('e'),
+		},
+		{
+			name:    "byte inside multi-byte rune",
+			in:      reflect.ValueOf("héllo"),
+			path:    valpath.ByteAt(2),
+			wantAny: byte(0xa9),
+		},
+		{
+			name:    "byte of named string",
+			in:      reflect.ValueOf(named("abc")),
+			path:    valpath.ByteAt(0),
+			wantAny: byte('a'),
+		},
+		{
+			name:    "byte out of range",
+			in:      reflect.ValueOf("abc"),
+			path:    valpath.ByteAt(3),
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+		{
+			name:    "rune counts code points",
+			in:      reflect.ValueOf("héllo"),
+			path:    valpath.RuneAt(2),
+			wantAny: 'l',
+		},
+		{
+			name:    "multi-byte rune",
+			in:      reflect.ValueOf("日本語"),
+			path:    valpath.RuneAt(1),
+			wantAny: '本',
+		},
+		{
+			name:    "invalid utf-8",
+			in:      reflect.ValueOf("a\xffb"),
+			path:    valpath.RuneAt(1),
+			wantAny: utf8.RuneError,
+		},
+		{
+			name:    "rune out of range",
+			in:      reflect.ValueOf("日本語"),
+			path:    valpath.RuneAt(3),
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+		{
+			name:    "negative rune",
+			in:      reflect.ValueOf("abc"),
+			path:    valpath.RuneAt(-1),
+			wantErr: valpath.ErrIndexOutOfRange,
+		},
+		{
+			name:    "not a string",
+			in:      reflect.ValueOf([]byte("abc")),
+			path:    valpath.ByteAt(0),
+			wantErr: valpath.ErrKindMismatch,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.path.Traverse(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Interface() != tt.wantAny {
+				t.Errorf("got %#v, want %#v", got.Interface(), tt.wantAny)
+			}
+			gotType, err := valpath.ResolveType(tt.in.Type(), tt.path)
+			if err != nil {
+				t.Fatalf("got ResolveType error %v, want no error", err)
+			}
+			if gotType != got.Type() {
+				t.Errorf("got ResolveType %v, want %v", gotType, got.Type())
+			}
+		})
+	}
+
+	t.Run("set rune", func(t *testing.T) {
+		got := map[string]string{"k": "héllo"}
+		path := valpath.Join(valpath.MapValueOfKey("k"), valpath.RuneAt(1))
+		if err := valpath.Set(reflect.ValueOf(got), path, reflect.ValueOf('ü')); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got["k"] != "hüllo" {
+			t.Errorf("got %q, want %q", got["k"], "hüllo")
+		}
+	})
+
+	t.Run("set byte", func(t *testing.T) {
+		got := struct{ S string }{S: "cat"}
+		path := valpath.Join(valpath.ExportedField("S"), valpath.ByteAt(0))
+		if err := valpath.Set(reflect.ValueOf(&got).Elem(), path, reflect.ValueOf(byte('b'))); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got.S != "bat" {
+			t.Errorf("got %q, want %q", got.S, "bat")
+		}
+	})
+
+	t.Run("set unaddressable", func(t *testing.T) {
+		err := valpath.Set(reflect.ValueOf("cat"), valpath.ByteAt(0), reflect.ValueOf(byte('b')))
+		if !errors.Is(err, valpath.ErrNotAddressable) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrNotAddressable)
+		}
+	})
+}
+
+func TestAs(t *testing.T) {
+	type holder struct {
+		Any   any
+		IFace testtypes.IFace
+	}
+	in := reflect.ValueOf(holder{Any: testtypes.Inner{Int: 1}, IFace: testtypes.IFaceImpl(2)})
+	testCases := []struct {
+		name     string
+		path     valpath.Path
+		wantAny  any
+		wantType reflect.Type
+		wantErr  error
+	}{
+		{
+			name:     "concrete type",
+			path:     valpath.Join(valpath.ExportedField("IFace"), valpath.As[testtypes.IFaceImpl]()),
+			wantAny:  testtypes.IFaceImpl(2),
+			wantType: reflect.TypeFor[testtypes.IFaceImpl](),
+		},
+		{
+			name:     "continue past assertion",
+			path:     valpath.Join(valpath.ExportedField("Any"), valpath.As[testtypes.Inner](), valpath.ExportedField("Int")),
+			wantAny:  1,
+			wantType: reflect.TypeFor[int](),
+		},
+		{
+			name:     "interface type",
+			path:     valpath.Join(valpath.ExportedField("IFace"), valpath.As[fmt.Stringer]()),
+			wantAny:  testtypes.IFaceImpl(2),
+			wantType: reflect.TypeFor[fmt.Stringer](),
+		},
+		{
+			name:     "value that isn't an interface",
+			path:     valpath.Join(valpath.ExportedField("IFace"), valpath.Inter(), valpath.AsType(reflect.TypeFor[testtypes.IFaceImpl]())),
+			wantAny:  testtypes.IFaceImpl(2),
+			wantType: valpath.Dynamic,
+		},
+		{
+			name:    "wrong concrete type",
+			path:    valpath.Join(valpath.ExportedField("Any"), valpath.As[*testtypes.Inner]()),
+			wantErr: valpath.ErrTypeMismatch,
+		},
+		{
+			name:    "interface not implemented",
+			path:    valpath.Join(valpath.ExportedField("Any"), valpath.As[testtypes.IFace]()),
+			wantErr: valpath.ErrTypeMismatch,
+		},
+		{
+			name:    "nil interface",
+			path:    valpath.Join(valpath.ExportedField("Any"), valpath.As[int]()),
+			wantErr: valpath.ErrNilPointer,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			v := in
+			if errors.Is(tt.wantErr, valpath.ErrNilPointer) {
+				v = reflect.ValueOf(holder{})
+			}
+			got, err := tt.path.Traverse(v)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Interface() != tt.wantAny {
+				t.Errorf("got %v, want %v", got.Interface(), tt.wantAny)
+			}
+			gotType, err := valpath.ResolveType(in.Type(), tt.path)
+			if err != nil {
+				t.Fatalf("got ResolveType error %v, want no error", err)
+			}
+			if gotType != tt.wantType {
+				t.Errorf("got ResolveType %v, want %v", gotType, tt.wantType)
+			}
+		})
+	}
+
+	t.Run("impossible assertion", func(t *testing.T) {
+		_, err := valpath.ResolveType(in.Type(), valpath.Join(valpath.ExportedField("IFace"), valpath.As[int]()))
+		if !errors.Is(err, valpath.ErrTypeMismatch) {
+			t.Errorf("got error %v, want %v", err, valpath.ErrTypeMismatch)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		got := holder{Any: testtypes.Inner{Int: 1}}
+		path := valpath.Join(valpath.ExportedField("Any"), valpath.As[testtypes.Inner](), valpath.ExportedField("Int"))
+		if err := valpath.Set(reflect.ValueOf(&got).Elem(), path, reflect.ValueOf(5)); err != nil {
+			t.Fatalf("got error %v, want no error", err)
+		}
+		if got.Any != (testtypes.Inner{Int: 5}) {
+			t.Errorf("got %v, want {5}", got.Any)
+		}
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		if valpath.Equal(valpath.As[int](), valpath.As[int64]()) {
+			t.Error("got equal assertions to different types")
+		}
+		if !valpath.Equal(valpath.As[int](), valpath.AsType(reflect.TypeFor[int]())) {
+			t.Error("got different assertions to the same type")
+		}
+	})
+}
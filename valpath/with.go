@@ -0,0 +1,71 @@
+package valpath
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+)
+
+var ErrNotCopyable = errors.New("value may be shared but cannot be copied")
+
+// With returns a copy of root in which the location addressed by p holds newVal, leaving
+// root itself unchanged.  Only the values along p are copied: structs and arrays by value,
+// and the targets of pointers, slices and maps by allocating new ones.  Everything off the
+// path is shared between root and the result, so neither should be modified in place
+// afterwards.  Paths that continue through values that can't be copied, such as pointers
+// returned by methods, fail with ErrNotCopyable.  newVal is converted as by Set.
+func With(root reflect.Value, p Path, newVal reflect.Value) (reflect.Value, error) {
+	if !root.IsValid() {
+		return zeroValue, newPathError(Empty(), root, ErrInvalidValue)
+	}
+	copied := reflect.New(root.Type()).Elem()
+	copied.Set(root)
+	// Building the list directly, rather than with Join, makes the root go through the same
+	// copying as every other step even when p has a single step.
+	steps := pathListElem(slices.Collect(p.elems()))
+	s, err := steps.edit(copied, &SetOptions{copyOnWrite: true})
+	if err != nil {
+		return zeroValue, err
+	}
+	if err := assign(s.v, newVal); err != nil {
+		return zeroValue, leafError(p, typeOf(s.v), err)
+	}
+	if s.store != nil {
+		if err := s.store(); err != nil {
+			return zeroValue, err
+		}
+	}
+	return copied, nil
+}
+
+// unshare replaces v, if it is a non-nil pointer, slice or map, with a shallow copy of the
+// value it refers to.  It reports whether v was replaced, and fails if v refers to a value
+// that may be shared but v can't be replaced, as with the results of methods.
+func unshare(v reflect.Value) (bool, error) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return false, nil
+		}
+		if !v.CanSet() {
+			return false, ErrNotCopyable
+		}
+	default:
+		return false, nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		ptr := reflect.New(v.Type().Elem())
+		ptr.Elem().Set(v.Elem())
+		v.Set(ptr)
+	case reflect.Slice:
+		v.Set(reflect.AppendSlice(reflect.MakeSlice(v.Type(), 0, v.Len()), v))
+	case reflect.Map:
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			m.SetMapIndex(iter.Key(), iter.Value())
+		}
+		v.Set(m)
+	}
+	return true, nil
+}
@@ -0,0 +1,175 @@
+package valpath_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-reflection-playground/testtypes"
+	"github.com/krelinga/go-reflection-playground/valpath"
+)
+
+func TestWith(t *testing.T) {
+	testCases := []struct {
+		name    string
+		path    valpath.Path
+		newVal  any
+		want    func(*setRoot)
+		wantErr error
+	}{
+		{
+			name:   "top-level field",
+			path:   valpath.ExportedField("Int"),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Int = 42 },
+		},
+		{
+			name:   "through pointer",
+			path:   valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Int")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Ptr.Int = 42 },
+		},
+		{
+			name:   "slice element",
+			path:   valpath.Join(valpath.ExportedField("Slice"), valpath.Index(1)),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Slice[1] = 42 },
+		},
+		{
+			name:   "new map entry",
+			path:   valpath.Join(valpath.ExportedField("Map"), valpath.MapValueOfKey("b")),
+			newVal: testtypes.Inner{Int: 42},
+			want:   func(r *setRoot) { r.Map["b"] = testtypes.Inner{Int: 42} },
+		},
+		{
+			name:   "through pointer in map",
+			path:   valpath.Join(valpath.ExportedField("PtrMap"), valpath.MapValueOfKey("a"), valpath.Deref(), valpath.ExportedField("Int")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.PtrMap["a"].Int = 42 },
+		},
+		{
+			name:   "nested map",
+			path:   valpath.Join(valpath.ExportedField("Nested"), valpath.MapValueOfKey("a"), valpath.MapValueOfKey("b")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Nested["a"]["b"] = 42 },
+		},
+		{
+			name:   "through interface holding pointer",
+			path:   valpath.Join(valpath.ExportedField("Any"), valpath.Inter(), valpath.Deref(), valpath.ExportedField("Int")),
+			newVal: 42,
+			want:   func(r *setRoot) { r.Any.(*testtypes.Inner).Int = 42 },
+		},
+		{
+			name:    "not assignable",
+			path:    valpath.ExportedField("Int"),
+			newVal:  "forty-two",
+			wantErr: valpath.ErrNotAssignable,
+		},
+		{
+			name:    "missing field",
+			path:    valpath.ExportedField("Missing"),
+			newVal:  42,
+			wantErr: valpath.ErrNoSuchField,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.want == nil) == (tt.wantErr == nil) {
+				t.Fatal("exactly one of want and wantErr must be set")
+			}
+			root := newSetRoot()
+			got, err := valpath.With(reflect.ValueOf(root), valpath.Join(valpath.Deref(), tt.path), reflect.ValueOf(tt.newVal))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want no error", err)
+			}
+			want := newSetRoot()
+			tt.want(want)
+			if !reflect.DeepEqual(got.Interface(), want) {
+				t.Errorf("got %+v, want %+v", got.Interface(), want)
+			}
+			if !reflect.DeepEqual(root, newSetRoot()) {
+				t.Errorf("root was modified to %+v", root)
+			}
+		})
+	}
+}
+
+func TestWithSharing(t *testing.T) {
+	root := *newSetRoot()
+	got, err := valpath.With(reflect.ValueOf(root), valpath.Join(valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Int")), reflect.ValueOf(42))
+	if err != nil {
+		t.Fatalf("got error %v, want no error", err)
+	}
+	updated := got.Interface().(setRoot)
+	if updated.Ptr == root.Ptr {
+		t.Error("pointer on the path was not copied")
+	}
+	if &updated.Slice[0] != &root.Slice[0] {
+		t.Error("slice off the path was copied")
+	}
+	if updated.PtrMap["a"] != root.PtrMap["a"] {
+		t.Error("map off the path was copied")
+	}
+	if root.Ptr.Int != 1 {
+		t.Errorf("root was modified to %d", root.Ptr.Int)
+	}
+}
+
+type ptrGetter struct {
+	Ptr *testtypes.Inner
+}
+
+func (g ptrGetter) Get() *testtypes.Inner { return g.Ptr }
+
+func TestWithShared(t *testing.T) {
+	testCases := []struct {
+		name    string
+		root    func() any
+		path    valpath.Path
+		want    any
+		wantErr error
+	}{
+		{
+			name: "promoted through embedded pointer",
+			root: func() any { return testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 1}} },
+			path: valpath.ExportedField("Int"),
+			want: testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 5}},
+		},
+		{
+			name: "field index through embedded pointer",
+			root: func() any { return testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 1}} },
+			path: valpath.FieldIndex([]int{0, 0}),
+			want: testtypes.OuterPtr{Inner: &testtypes.Inner{Int: 5}},
+		},
+		{
+			name:    "through method result",
+			root:    func() any { return ptrGetter{Ptr: &testtypes.Inner{Int: 1}} },
+			path:    valpath.Join(valpath.Method("Get"), valpath.Deref(), valpath.ExportedField("Int")),
+			wantErr: valpath.ErrNotCopyable,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			root := tt.root()
+			got, err := valpath.With(reflect.ValueOf(root), tt.path, reflect.ValueOf(5))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("got error %v, want no error", err)
+			} else if !reflect.DeepEqual(got.Interface(), tt.want) {
+				t.Errorf("got %+v, want %+v", got.Interface(), tt.want)
+			}
+			if !reflect.DeepEqual(root, tt.root()) {
+				t.Errorf("root was modified to %+v", root)
+			}
+		})
+	}
+}
@@ -66,6 +66,114 @@ func (allExportedFieldsPat) elems() iter.Seq[Pattern] {
 	return iters.Single(AllExportedFields())
 }
 
+func AllFields() Pattern {
+	return allFieldsPat{}
+}
+
+type allFieldsPat struct{}
+
+func (allFieldsPat) String() string {
+	return "<all fields>"
+}
+
+func (allFieldsPat) Match(v reflect.Value) iter.Seq2[valpath.Path, reflect.Value] {
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return iters.Empty2[valpath.Path, reflect.Value]()
+	}
+	return func(yield func(valpath.Path, reflect.Value) bool) {
+		for _, f := range reflect.VisibleFields(v.Type()) {
+			var p valpath.Path
+			if f.IsExported() {
+				p = valpath.ExportedField(f.Name)
+			} else {
+				p = valpath.UnexportedField(f.Name)
+			}
+			// Fields promoted through nil embedded pointers are skipped.
+			if found, err := p.Traverse(v); err == nil {
+				if !yield(p, found) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (allFieldsPat) elems() iter.Seq[Pattern] {
+	return iters.Single(AllFields())
+}
+
+// AllTaggedFields matches the fields of a struct as named by the struct tag key, using the
+// same rules as valpath.TaggedField.
+func AllTaggedFields(key string) Pattern {
+	return allTaggedFieldsPat{key: key}
+}
+
+type allTaggedFieldsPat struct {
+	key string
+}
+
+func (p allTaggedFieldsPat) String() string {
+	return "<all " + p.key + " fields>"
+}
+
+func (p allTaggedFieldsPat) Match(v reflect.Value) iter.Seq2[valpath.Path, reflect.Value] {
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return iters.Empty2[valpath.Path, reflect.Value]()
+	}
+	return func(yield func(valpath.Path, reflect.Value) bool) {
+		for _, name := range valpath.TagNames(v.Type(), p.key) {
+			path := valpath.TaggedField(p.key, name)
+			// Fields promoted through nil embedded pointers are skipped.
+			if found, err := path.Traverse(v); err == nil {
+				if !yield(path, found) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p allTaggedFieldsPat) elems() iter.Seq[Pattern] {
+	return iters.Single(Pattern(p))
+}
+
+func AllGetters() Pattern {
+	return allGettersPat{}
+}
+
+type allGettersPat struct{}
+
+func (allGettersPat) String() string {
+	return "<all getters>"
+}
+
+func (allGettersPat) Match(v reflect.Value) iter.Seq2[valpath.Path, reflect.Value] {
+	if !v.IsValid() {
+		return iters.Empty2[valpath.Path, reflect.Value]()
+	}
+	methodSet := v.Type()
+	// Pointer methods can be called on addressable values, but a pointer's own methods are
+	// those of its element type, and taking its address again would hide them.
+	if v.Kind() != reflect.Interface && v.Kind() != reflect.Pointer && v.CanAddr() {
+		methodSet = reflect.PointerTo(methodSet)
+	}
+	return func(yield func(valpath.Path, reflect.Value) bool) {
+		for i := range methodSet.NumMethod() {
+			// Methods that aren't getters, or that fail, are skipped.
+			p := valpath.Method(methodSet.Method(i).Name)
+			if found, err := p.Traverse(v); err == nil {
+				if !yield(p, found) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (allGettersPat) elems() iter.Seq[Pattern] {
+	return iters.Single(AllGetters())
+}
+
 func AllMapKeys() Pattern {
 	return allMapKeysPat{}
 }
@@ -83,7 +191,11 @@ func (allMapKeysPat) Match(v reflect.Value) iter.Seq2[valpath.Path, reflect.Valu
 
 	keys := slices.Values(v.MapKeys())
 	pairs := iters.Map(keys, func(k reflect.Value) iters.Pair[valpath.Path, reflect.Value] {
-		return iters.NewPair(valpath.MapKey(k), v.MapIndex(k))
+		// Keys of maps with interface key types are addressed by their dynamic values.
+		if k.Kind() == reflect.Interface && !k.IsNil() {
+			k = k.Elem()
+		}
+		return iters.NewPair(valpath.MapKeyOf(k), k)
 	})
 	return iters.FromPairs(pairs)
 }
@@ -116,7 +228,7 @@ func (allMapValuesPat) Match(v reflect.Value) iter.Seq2[valpath.Path, reflect.Va
 		}
 	}
 	return iters.Map2(entries, func(k, v reflect.Value) (valpath.Path, reflect.Value) {
-		return valpath.MapValueOfKey(k), v
+		return valpath.MapValueOfKeyOf(k), v
 	})
 }
 
@@ -124,6 +236,92 @@ func (allMapValuesPat) elems() iter.Seq[Pattern] {
 	return iters.Single(AllMapValues())
 }
 
+func AllRunes() Pattern {
+	return allRunesPat{}
+}
+
+type allRunesPat struct{}
+
+func (allRunesPat) String() string {
+	return "<all runes>"
+}
+
+func (allRunesPat) Match(v reflect.Value) iter.Seq2[valpath.Path, reflect.Value] {
+	if !v.IsValid() || v.Kind() != reflect.String {
+		return iters.Empty2[valpath.Path, reflect.Value]()
+	}
+	return func(yield func(valpath.Path, reflect.Value) bool) {
+		i := 0
+		for _, r := range v.String() {
+			if !yield(valpath.RuneAt(i), reflect.ValueOf(r)) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+func (allRunesPat) elems() iter.Seq[Pattern] {
+	return iters.Single(AllRunes())
+}
+
+// OfType matches a value that has type t or, if t is an interface type, implements it.
+// Interface values are matched by the values inside them.  The yielded path is a type
+// assertion to t.
+func OfType(t reflect.Type) Pattern {
+	return ofTypePat{t: t}
+}
+
+type ofTypePat struct {
+	t reflect.Type
+}
+
+func (p ofTypePat) String() string {
+	return "<of type " + p.t.String() + ">"
+}
+
+func (p ofTypePat) Match(v reflect.Value) iter.Seq2[valpath.Path, reflect.Value] {
+	return Path(valpath.AsType(p.t)).Match(v)
+}
+
+func (p ofTypePat) elems() iter.Seq[Pattern] {
+	return iters.Single(Pattern(p))
+}
+
+// OfKind matches a value of kind k.  Interface values are matched by the values inside them,
+// unless k is reflect.Interface, and the yielded path then unwraps the interface.
+func OfKind(k reflect.Kind) Pattern {
+	return ofKindPat{k: k}
+}
+
+type ofKindPat struct {
+	k reflect.Kind
+}
+
+func (p ofKindPat) String() string {
+	return "<of kind " + p.k.String() + ">"
+}
+
+func (p ofKindPat) Match(v reflect.Value) iter.Seq2[valpath.Path, reflect.Value] {
+	if !v.IsValid() {
+		return iters.Empty2[valpath.Path, reflect.Value]()
+	}
+	if v.Kind() == reflect.Interface && p.k != reflect.Interface {
+		if v.IsNil() || v.Elem().Kind() != p.k {
+			return iters.Empty2[valpath.Path, reflect.Value]()
+		}
+		return iters.Single2(valpath.Inter(), v.Elem())
+	}
+	if v.Kind() != p.k {
+		return iters.Empty2[valpath.Path, reflect.Value]()
+	}
+	return iters.Single2(valpath.Empty(), v)
+}
+
+func (p ofKindPat) elems() iter.Seq[Pattern] {
+	return iters.Single(Pattern(p))
+}
+
 func Join(children ...Pattern) Pattern {
 	asIter := slices.Values(children)
 	nonNil := iters.Filter(asIter, func(e Pattern) bool {
@@ -168,7 +366,7 @@ func (j joinedPat) Match(v reflect.Value) iter.Seq2[valpath.Path, reflect.Value]
 			oldVal := in.Two
 			matches := elem.Match(oldVal)
 			withFixedPath := iters.Map2(matches, func(p valpath.Path, v reflect.Value) (valpath.Path, reflect.Value) {
-				return valpath.Join(oldPath, p), v
+				return valpath.Join(oldPath, p), valpath.ReadOnlyAfter(oldPath, v)
 			})
 			return iters.ToPairs(withFixedPath)
 		})
@@ -206,3 +404,70 @@ func (emptyPat) elems() iter.Seq[Pattern] {
 func Empty() Pattern {
 	return emptyPat{}
 }
+
+// MatchWith is like p.Match, but with behavior controlled by opts.  With AutoDeref, non-nil
+// pointers and interfaces are passed through before each part of p that matches within
+// structs, maps, or strings, as valpath.TraverseWith does for path steps, and the yielded
+// paths include explicit Deref and Inter steps for them.
+func MatchWith(p Pattern, v reflect.Value, opts valpath.Options) iter.Seq2[valpath.Path, reflect.Value] {
+	if !opts.AutoDeref {
+		return p.Match(v)
+	}
+	if !v.IsValid() {
+		return iters.Empty2[valpath.Path, reflect.Value]()
+	}
+
+	out := []iters.Pair[valpath.Path, reflect.Value]{iters.NewPair(valpath.Empty(), v)}
+	for elem := range p.elems() {
+		var next []iters.Pair[valpath.Path, reflect.Value]
+		for _, in := range out {
+			for path, found := range matchAutoDeref(elem, in.Two, opts) {
+				next = append(next, iters.NewPair(valpath.Join(in.One, path), valpath.ReadOnlyAfter(in.One, found)))
+			}
+		}
+		out = next
+	}
+	return iters.FromPairs(slices.Values(out))
+}
+
+func matchAutoDeref(elem Pattern, v reflect.Value, opts valpath.Options) iter.Seq2[valpath.Path, reflect.Value] {
+	if p, ok := elem.(pathPat); ok {
+		found, taken, err := valpath.TraverseWith(v, p.Path, opts)
+		if err != nil {
+			return iters.Empty2[valpath.Path, reflect.Value]()
+		}
+		return iters.Single2(taken, found)
+	}
+	if !autoDerefs(elem) {
+		return elem.Match(v)
+	}
+
+	var peeled []valpath.Path
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return iters.Empty2[valpath.Path, reflect.Value]()
+		}
+		if v.Kind() == reflect.Pointer {
+			peeled = append(peeled, valpath.Deref())
+		} else {
+			peeled = append(peeled, valpath.Inter())
+		}
+		v = v.Elem()
+	}
+	prefix := valpath.Join(peeled...)
+	return iters.Map2(elem.Match(v), func(p valpath.Path, found reflect.Value) (valpath.Path, reflect.Value) {
+		return valpath.Join(prefix, p), found
+	})
+}
+
+// autoDerefs reports whether elem is preceded by automatic dereferencing under AutoDeref,
+// because it only matches within structs, maps, or strings.  Patterns like AllGetters,
+// OfType, and OfKind can match pointers and interfaces themselves, so they see them as is.
+func autoDerefs(elem Pattern) bool {
+	switch elem.(type) {
+	case allExportedFieldsPat, allFieldsPat, allTaggedFieldsPat, allMapKeysPat, allMapValuesPat, allRunesPat:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,11 +1,14 @@
 package valpattern_test
 
 import (
+	"errors"
+	"iter"
 	"reflect"
 	"slices"
 	"testing"
 
 	"github.com/krelinga/go-iters"
+	"github.com/krelinga/go-reflection-playground/testtypes"
 	"github.com/krelinga/go-reflection-playground/valpath"
 	"github.com/krelinga/go-reflection-playground/valpattern"
 	"github.com/krelinga/go-sets"
@@ -21,7 +24,7 @@ func checkEqual(t *testing.T, got, want []iters.Pair[valpath.Path, reflect.Value
 wants:
 	for _, w := range want {
 		for i, g := range got {
-			if !claimed.Has(i) && reflect.DeepEqual(g, w) {
+			if !claimed.Has(i) && pairEqual(g, w) {
 				claimed.Add(i)
 				continue wants
 			}
@@ -33,6 +36,21 @@ wants:
 	t.Errorf("mismatch: got %v, want %v", got, want)
 }
 
+// pairEqual compares values by their contents, since reflect.Value itself can't be compared
+// meaningfully with reflect.DeepEqual.
+func pairEqual(got, want iters.Pair[valpath.Path, reflect.Value]) bool {
+	if !valpath.Equal(got.One, want.One) {
+		return false
+	}
+	if got.Two.IsValid() != want.Two.IsValid() {
+		return false
+	}
+	if !got.Two.IsValid() {
+		return true
+	}
+	return got.Two.Type() == want.Two.Type() && reflect.DeepEqual(got.Two.Interface(), want.Two.Interface())
+}
+
 func TestPattern(t *testing.T) {
 	type Sub struct {
 		name    string
@@ -58,6 +76,176 @@ func TestPattern(t *testing.T) {
 				// TODO: add many more tests for other kinds of patterns, and more input values too.
 			},
 		},
+		{
+			name: "struct with unexported fields",
+			in:   reflect.ValueOf(private{count: 1, Public: 2}),
+			sub: []Sub{
+				{
+					name:    "all exported fields",
+					pattern: valpattern.AllExportedFields(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.ExportedField("Public"), reflect.ValueOf(2)),
+					},
+				},
+				{
+					name:    "all fields",
+					pattern: valpattern.AllFields(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.UnexportedField("count"), reflect.ValueOf(1)),
+						iters.NewPair(valpath.ExportedField("Public"), reflect.ValueOf(2)),
+					},
+				},
+			},
+		},
+		{
+			name: "struct with json tags",
+			in:   reflect.ValueOf(tagged{Inner: &testtypes.Inner{Int: 1}, Name: "n", Skipped: 2, count: 3}),
+			sub: []Sub{
+				{
+					name:    "all json fields",
+					pattern: valpattern.AllTaggedFields("json"),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.TaggedField("json", "name"), reflect.ValueOf("n")),
+						iters.NewPair(valpath.TaggedField("json", "Int"), reflect.ValueOf(1)),
+					},
+				},
+			},
+		},
+		{
+			name: "struct with json tags and nil embedded pointer",
+			in:   reflect.ValueOf(tagged{Name: "n"}),
+			sub: []Sub{
+				{
+					name:    "all json fields",
+					pattern: valpattern.AllTaggedFields("json"),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.TaggedField("json", "name"), reflect.ValueOf("n")),
+					},
+				},
+			},
+		},
+		{
+			name: "interface value",
+			in:   testtypes.NewIFaceValue(42),
+			sub: []Sub{
+				{
+					name:    "all getters",
+					pattern: valpattern.AllGetters(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.Method("String"), reflect.ValueOf("42")),
+					},
+				},
+			},
+		},
+		{
+			name: "addressable getters",
+			in:   reflect.ValueOf(&getters{n: 42}).Elem(),
+			sub: []Sub{
+				{
+					name:    "all getters",
+					pattern: valpattern.AllGetters(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.Method("Double"), reflect.ValueOf(84)),
+						iters.NewPair(valpath.Method("PtrValue"), reflect.ValueOf(42)),
+						iters.NewPair(valpath.Method("Value"), reflect.ValueOf(42)),
+					},
+				},
+			},
+		},
+		{
+			name: "addressable pointer to getters",
+			in:   reflect.ValueOf(&struct{ P *getters }{P: &getters{n: 42}}).Elem().Field(0),
+			sub: []Sub{
+				{
+					name:    "all getters",
+					pattern: valpattern.AllGetters(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.Method("Double"), reflect.ValueOf(84)),
+						iters.NewPair(valpath.Method("PtrValue"), reflect.ValueOf(42)),
+						iters.NewPair(valpath.Method("Value"), reflect.ValueOf(42)),
+					},
+				},
+			},
+		},
+		{
+			name: "map with int64 keys",
+			in:   reflect.ValueOf(map[int64]string{1: "one", 2: "two"}),
+			sub: []Sub{
+				{
+					name:    "all map keys",
+					pattern: valpattern.AllMapKeys(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.MapKey(int64(1)), reflect.ValueOf(int64(1))),
+						iters.NewPair(valpath.MapKey(int64(2)), reflect.ValueOf(int64(2))),
+					},
+				},
+				{
+					name:    "all map values",
+					pattern: valpattern.AllMapValues(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.MapValueOfKey(int64(1)), reflect.ValueOf("one")),
+						iters.NewPair(valpath.MapValueOfKey(int64(2)), reflect.ValueOf("two")),
+					},
+				},
+			},
+		},
+		{
+			name: "map with interface keys",
+			in:   reflect.ValueOf(map[any]int{"a": 1, 2: 2}),
+			sub: []Sub{
+				{
+					name:    "all map keys",
+					pattern: valpattern.AllMapKeys(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.MapKey("a"), reflect.ValueOf("a")),
+						iters.NewPair(valpath.MapKey(2), reflect.ValueOf(2)),
+					},
+				},
+				{
+					name:    "all map values",
+					pattern: valpattern.AllMapValues(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.MapValueOfKey("a"), reflect.ValueOf(1)),
+						iters.NewPair(valpath.MapValueOfKey(2), reflect.ValueOf(2)),
+					},
+				},
+			},
+		},
+		{
+			name: "string",
+			in:   reflect.ValueOf("héllo"),
+			sub: []Sub{
+				{
+					name:    "all runes",
+					pattern: valpattern.AllRunes(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.RuneAt(0), reflect.ValueOf('h')),
+						iters.NewPair(valpath.RuneAt(1), reflect.ValueOf('é')),
+						iters.NewPair(valpath.RuneAt(2), reflect.ValueOf('l')),
+						iters.NewPair(valpath.RuneAt(3), reflect.ValueOf('l')),
+						iters.NewPair(valpath.RuneAt(4), reflect.ValueOf('o')),
+					},
+				},
+				{
+					name:    "all exported fields",
+					pattern: valpattern.AllExportedFields(),
+				},
+			},
+		},
+		{
+			name: "unaddressable getters",
+			in:   reflect.ValueOf(getters{n: 42}),
+			sub: []Sub{
+				{
+					name:    "all getters",
+					pattern: valpattern.AllGetters(),
+					want: []iters.Pair[valpath.Path, reflect.Value]{
+						iters.NewPair(valpath.Method("Double"), reflect.ValueOf(84)),
+						iters.NewPair(valpath.Method("Value"), reflect.ValueOf(42)),
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
@@ -70,3 +258,275 @@ func TestPattern(t *testing.T) {
 		})
 	}
 }
+
+// TestRetraverse checks that every path yielded by a pattern leads back to the value it was
+// yielded with.
+func TestRetraverse(t *testing.T) {
+	type namedKey string
+	type root struct {
+		Ints   map[int64]string
+		Named  map[namedKey]*testtypes.Inner
+		Any    map[any][]int
+		Nested map[string]map[int]bool
+		Inner  testtypes.Inner
+		priv   private
+	}
+	in := reflect.ValueOf(&root{
+		Ints:   map[int64]string{1: "one", 2: "two"},
+		Named:  map[namedKey]*testtypes.Inner{"a": {Int: 1}},
+		Any:    map[any][]int{"s": {1}, 3: {2, 3}, 4.5: nil},
+		Nested: map[string]map[int]bool{"x": {1: true, 2: false}},
+		Inner:  testtypes.Inner{Int: 5},
+		priv:   private{count: 1, Public: 2},
+	}).Elem()
+	patterns := []valpattern.Pattern{
+		valpattern.AllFields(),
+		valpattern.Join(valpattern.AllExportedFields(), valpattern.AllMapKeys()),
+		valpattern.Join(valpattern.AllExportedFields(), valpattern.AllMapValues()),
+		valpattern.Join(valpattern.AllExportedFields(), valpattern.AllMapValues(), valpattern.AllMapKeys()),
+		valpattern.Join(valpattern.AllExportedFields(), valpattern.AllMapValues(), valpattern.AllMapValues()),
+		valpattern.Join(valpattern.AllFields(), valpattern.AllFields()),
+		valpattern.Join(valpattern.AllExportedFields(), valpattern.AllMapKeys(), valpattern.AllRunes()),
+	}
+	for _, pattern := range patterns {
+		t.Run(pattern.String(), func(t *testing.T) {
+			count := 0
+			for path, want := range pattern.Match(in) {
+				count++
+				got, err := path.Traverse(in)
+				if err != nil {
+					t.Errorf("%s: got error %v, want no error", path, err)
+					continue
+				}
+				if !pairEqual(iters.NewPair(path, got), iters.NewPair(path, want)) {
+					t.Errorf("%s: got %v, want %v", path, got, want)
+				}
+			}
+			if count == 0 {
+				t.Error("got no matches, want some")
+			}
+		})
+	}
+}
+
+// TestReadOnlyMatches checks that values reached through unexported fields can't be modified,
+// even when a later pattern follows a pointer.
+func TestReadOnlyMatches(t *testing.T) {
+	type root struct {
+		inner *testtypes.Inner
+	}
+	in := reflect.ValueOf(&root{inner: &testtypes.Inner{Int: 1}}).Elem()
+	pattern := valpattern.Join(valpattern.AllFields(), valpattern.Path(valpath.Deref()), valpattern.AllExportedFields())
+	for name, matches := range map[string]iter.Seq2[valpath.Path, reflect.Value]{
+		"Match":     pattern.Match(in),
+		"MatchWith": valpattern.MatchWith(pattern, in, valpath.Options{AutoDeref: true}),
+	} {
+		count := 0
+		for path, found := range matches {
+			count++
+			if found.CanSet() {
+				t.Errorf("%s: %s: got settable value, want read-only value", name, path)
+			}
+		}
+		if count != 1 {
+			t.Errorf("%s: got %d matches, want 1", name, count)
+		}
+	}
+}
+
+func TestMatchWith(t *testing.T) {
+	type root struct {
+		Ptr   *testtypes.Inner
+		Nil   *testtypes.Inner
+		IFace any
+		Map   *map[string]int
+	}
+	in := reflect.ValueOf(&root{
+		Ptr:   &testtypes.Inner{Int: 1},
+		IFace: testtypes.Inner{Int: 2},
+		Map:   &map[string]int{"a": 3},
+	})
+	autoDeref := valpath.Options{AutoDeref: true}
+	testCases := []struct {
+		name    string
+		pattern valpattern.Pattern
+		opts    valpath.Options
+		want    []iters.Pair[valpath.Path, reflect.Value]
+	}{
+		{
+			name:    "without auto deref",
+			pattern: valpattern.AllExportedFields(),
+			want:    nil,
+		},
+		{
+			name:    "fields of fields",
+			pattern: valpattern.Join(valpattern.AllExportedFields(), valpattern.AllExportedFields()),
+			opts:    autoDeref,
+			want: []iters.Pair[valpath.Path, reflect.Value]{
+				iters.NewPair(valpath.Join(
+					valpath.Deref(), valpath.ExportedField("Ptr"), valpath.Deref(), valpath.ExportedField("Int")), reflect.ValueOf(1)),
+				iters.NewPair(valpath.Join(
+					valpath.Deref(), valpath.ExportedField("IFace"), valpath.Inter(), valpath.ExportedField("Int")), reflect.ValueOf(2)),
+			},
+		},
+		{
+			name: "path then map values",
+			pattern: valpattern.Join(
+				valpattern.Path(valpath.ExportedField("Map")), valpattern.AllMapValues()),
+			opts: autoDeref,
+			want: []iters.Pair[valpath.Path, reflect.Value]{
+				iters.NewPair(valpath.Join(
+					valpath.Deref(), valpath.ExportedField("Map"), valpath.Deref(), valpath.MapValueOfKey("a")), reflect.ValueOf(3)),
+			},
+		},
+		{
+			name:    "pointer type",
+			pattern: valpattern.OfType(in.Type()),
+			opts:    autoDeref,
+			want: []iters.Pair[valpath.Path, reflect.Value]{
+				iters.NewPair(valpath.AsType(in.Type()), in),
+			},
+		},
+		{
+			name:    "pointer fields",
+			pattern: valpattern.Join(valpattern.AllExportedFields(), valpattern.OfKind(reflect.Pointer)),
+			opts:    autoDeref,
+			want: []iters.Pair[valpath.Path, reflect.Value]{
+				iters.NewPair(valpath.Join(valpath.Deref(), valpath.ExportedField("Ptr")), in.Elem().Field(0)),
+				iters.NewPair(valpath.Join(valpath.Deref(), valpath.ExportedField("Nil")), in.Elem().Field(1)),
+				iters.NewPair(valpath.Join(valpath.Deref(), valpath.ExportedField("Map")), in.Elem().Field(3)),
+			},
+		},
+		{
+			name:    "empty",
+			pattern: valpattern.Empty(),
+			opts:    autoDeref,
+			want: []iters.Pair[valpath.Path, reflect.Value]{
+				iters.NewPair(valpath.Empty(), in),
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Collect(iters.ToPairs(valpattern.MatchWith(tt.pattern, in, tt.opts)))
+			checkEqual(t, got, tt.want)
+			for _, pair := range got {
+				found, err := pair.One.Traverse(in)
+				if err != nil {
+					t.Errorf("%s: got error %v, want no error", pair.One, err)
+				} else if !pairEqual(iters.NewPair(pair.One, found), pair) {
+					t.Errorf("%s: got %v, want %v", pair.One, found, pair.Two)
+				}
+			}
+		})
+	}
+}
+
+func TestOfTypeAndKind(t *testing.T) {
+	items := []testtypes.IFace{testtypes.IFaceImpl(1), nil, stringer("s"), testtypes.IFaceImpl(2)}
+	in := reflect.ValueOf(items)
+	// matchElems matches pattern against each element of in.
+	matchElems := func(pattern valpattern.Pattern) []iters.Pair[valpath.Path, reflect.Value] {
+		var out []iters.Pair[valpath.Path, reflect.Value]
+		for i := range items {
+			joined := valpattern.Join(valpattern.Path(valpath.Index(i)), pattern)
+			out = append(out, slices.Collect(iters.ToPairs(joined.Match(in)))...)
+		}
+		return out
+	}
+	testCases := []struct {
+		name    string
+		pattern valpattern.Pattern
+		want    []iters.Pair[valpath.Path, reflect.Value]
+	}{
+		{
+			name:    "concrete type",
+			pattern: valpattern.OfType(reflect.TypeFor[testtypes.IFaceImpl]()),
+			want: []iters.Pair[valpath.Path, reflect.Value]{
+				iters.NewPair(valpath.Join(valpath.Index(0), valpath.As[testtypes.IFaceImpl]()), reflect.ValueOf(testtypes.IFaceImpl(1))),
+				iters.NewPair(valpath.Join(valpath.Index(3), valpath.As[testtypes.IFaceImpl]()), reflect.ValueOf(testtypes.IFaceImpl(2))),
+			},
+		},
+		{
+			name:    "kind",
+			pattern: valpattern.OfKind(reflect.String),
+			want: []iters.Pair[valpath.Path, reflect.Value]{
+				iters.NewPair(valpath.Join(valpath.Index(2), valpath.Inter()), reflect.ValueOf(stringer("s"))),
+			},
+		},
+		{
+			name:    "interface kind",
+			pattern: valpattern.OfKind(reflect.Interface),
+			want: []iters.Pair[valpath.Path, reflect.Value]{
+				iters.NewPair(valpath.Index(0), in.Index(0)),
+				iters.NewPair(valpath.Index(1), in.Index(1)),
+				iters.NewPair(valpath.Index(2), in.Index(2)),
+				iters.NewPair(valpath.Index(3), in.Index(3)),
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			checkEqual(t, matchElems(tt.pattern), tt.want)
+		})
+	}
+
+	t.Run("interface type", func(t *testing.T) {
+		got := matchElems(valpattern.OfType(reflect.TypeFor[interface{ Len() int }]()))
+		if len(got) != 1 || !valpath.Equal(got[0].One, valpath.Join(valpath.Index(2), valpath.As[interface{ Len() int }]())) {
+			t.Errorf("got %v, want only element 2", got)
+		}
+	})
+}
+
+type stringer string
+
+func (s stringer) String() string {
+	return string(s)
+}
+
+func (s stringer) Len() int {
+	return len(s)
+}
+
+type getters struct {
+	n int
+}
+
+func (g getters) Value() int {
+	return g.n
+}
+
+func (g getters) Double() (int, error) {
+	return 2 * g.n, nil
+}
+
+func (g getters) Failing() (int, error) {
+	return 0, errors.New("failed")
+}
+
+func (g getters) Plus(i int) int {
+	return g.n + i
+}
+
+func (g *getters) PtrValue() int {
+	return g.n
+}
+
+// Reset isn't a getter, so enumerating getters must not call it.
+func (g *getters) Reset() error {
+	g.n = 0
+	return nil
+}
+
+type tagged struct {
+	*testtypes.Inner
+	Name    string `json:"name"`
+	Skipped int    `json:"-"`
+	count   int
+}
+
+type private struct {
+	count  int
+	Public int
+}